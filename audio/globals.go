@@ -1,9 +1,14 @@
 package audio
 
-import "github.com/200sc/klangsynthese/font"
+import (
+	"sync"
+
+	"github.com/200sc/klangsynthese/font"
+)
 
 var (
-	loaded = make(map[string]Data)
+	loaded     = make(map[string]Data)
+	loadedLock = sync.RWMutex{}
 	// DefFont is the font used for default functions. It can be publicly
 	// modified to apply a default font to generated audios through def
 	// methods. If it is not modified, it is a font of zero filters.
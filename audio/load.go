@@ -35,8 +35,11 @@ func GetSounds(fileNames ...string) ([]Data, error) {
 
 // Get without a font will just return the raw audio data
 func Get(filename string) (Data, error) {
-	if IsLoaded(filename) {
-		return loaded[filename], nil
+	loadedLock.RLock()
+	data, ok := loaded[filename]
+	loadedLock.RUnlock()
+	if ok {
+		return data, nil
 	}
 	return nil, oakerr.NotFound{InputName: filename}
 }
@@ -49,39 +52,53 @@ func Get(filename string) (Data, error) {
 // one stored in the loeaded map.
 func Load(directory, filename string) (Data, error) {
 	dlog.Verb("Loading", directory, filename)
-	if !IsLoaded(filename) {
-		f, err := fileutil.Open(filepath.Join(directory, filename))
-		if err != nil {
-			return nil, err
-		}
-		defer f.Close()
-		var buffer audio.Audio
-		end := strings.ToLower(filename[len(filename)-4:])
-		switch end {
-		case ".wav":
-			buffer, err = wav.Load(f)
-		case ".mp3":
-			buffer, err = mp3.Load(f)
-		default:
-			return nil, oakerr.UnsupportedFormat{Format: end}
-		}
-		if err != nil {
-			return nil, err
-		}
-		loaded[filename] = buffer.(audio.FullAudio)
+	if data, err := Get(filename); err == nil {
+		return data, nil
+	}
+	// Files are decoded without holding the lock, so that other files can
+	// be read and loaded meanwhile
+	f, err := fileutil.Open(filepath.Join(directory, filename))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var buffer audio.Audio
+	end := strings.ToLower(filename[len(filename)-4:])
+	switch end {
+	case ".wav":
+		buffer, err = wav.Load(f)
+	case ".mp3":
+		buffer, err = mp3.Load(f)
+	default:
+		return nil, oakerr.UnsupportedFormat{Format: end}
+	}
+	if err != nil {
+		return nil, err
+	}
+	loadedLock.Lock()
+	defer loadedLock.Unlock()
+	// If the file was loaded while this was decoding, the first file
+	// loaded is kept
+	if data, ok := loaded[filename]; ok {
+		return data, nil
 	}
+	loaded[filename] = buffer.(audio.FullAudio)
 	return loaded[filename], nil
 }
 
 // Unload removes an element from the loaded map. If the element does not
 // exist, it does nothing.
 func Unload(filename string) {
+	loadedLock.Lock()
 	delete(loaded, filename)
+	loadedLock.Unlock()
 }
 
 // IsLoaded is shorthand for (if _, ok := loaded[filename]; ok)
 func IsLoaded(filename string) bool {
+	loadedLock.RLock()
 	_, ok := loaded[filename]
+	loadedLock.RUnlock()
 	return ok
 }
 
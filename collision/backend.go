@@ -0,0 +1,78 @@
+package collision
+
+import (
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/oakerr"
+)
+
+// A Backend stores the spaces of a Tree and finds them by location. Trees
+// hold their write lock around calls that modify their backend, and their
+// read lock around queries, so backends do not need to be safe for
+// concurrent use themselves, but must allow concurrent queries. Calling a
+// backend's methods directly, rather than through its tree, is not safe
+// while the tree is in use.
+//
+// Two backends are provided: an Rtree, the default, which suits spaces of
+// any size and distribution, and a SpatialHash, which suits many similarly
+// sized spaces which move often.
+type Backend interface {
+	// Insert adds a space to the backend.
+	Insert(sp *Space)
+	// Delete removes a space from the backend, returning whether it
+	// was found.
+	Delete(sp *Space) bool
+	// Update moves a space to a new location, setting its Location to
+	// rect. If the space was not in the backend, it is added.
+	Update(sp *Space, rect floatgeom.Rect3)
+	// SearchIntersect returns all spaces that intersect bb.
+	SearchIntersect(bb floatgeom.Rect3) []*Space
+	// NearestNeighbor returns the closest space to p, or nil if the
+	// backend is empty.
+	NearestNeighbor(p floatgeom.Point3) *Space
+	// NearestNeighbors returns the k closest spaces to p in order of
+	// increasing distance. If there are fewer than k spaces, the
+	// remainder of the returned slice is nil.
+	NearestNeighbors(k int, p floatgeom.Point3) []*Space
+	// Spaces returns every space in the backend.
+	Spaces() []*Space
+	// Clear removes all spaces from the backend.
+	Clear()
+}
+
+// NewBackendTree returns a collision Tree which stores its spaces in the
+// given backend.
+func NewBackendTree(b Backend) (*Tree, error) {
+	if b == nil {
+		return nil, oakerr.NilInput{InputName: "b"}
+	}
+	return &Tree{
+		Backend: b,
+	}, nil
+}
+
+// NewHashTree returns a collision Tree backed by a SpatialHash with cells
+// of the given width and height.
+func NewHashTree(cellW, cellH float64) (*Tree, error) {
+	h, err := NewSpatialHash(cellW, cellH)
+	if err != nil {
+		return nil, err
+	}
+	return NewBackendTree(h)
+}
+
+// Update satisfies Backend
+func (tree *Rtree) Update(sp *Space, rect floatgeom.Rect3) {
+	tree.Delete(sp)
+	sp.Location = rect
+	tree.Insert(sp)
+}
+
+// Spaces satisfies Backend
+func (tree *Rtree) Spaces() []*Space {
+	return tree.spaces(tree.root, make([]*Space, 0, tree.size))
+}
+
+// Clear satisfies Backend
+func (tree *Rtree) Clear() {
+	*tree = *newTree(tree.MinChildren, tree.MaxChildren)
+}
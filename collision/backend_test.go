@@ -0,0 +1,162 @@
+package collision
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+	"github.com/stretchr/testify/assert"
+)
+
+// testBackends are run through tests and benchmarks which should act the
+// same on every backend. new returns a backend suited to the small spaces
+// of rtree_test, and newLarge one suited to those of splitTree_test.
+var testBackends = []struct {
+	name     string
+	new      func() Backend
+	newLarge func() Backend
+}{
+	{
+		name: "Rtree",
+		new: func() Backend {
+			return newTree(3, 3)
+		},
+		newLarge: func() Backend {
+			return newTree(DefaultMinChildren, DefaultMaxChildren)
+		},
+	}, {
+		name: "SpatialHash",
+		new: func() Backend {
+			h, _ := NewSpatialHash(2, 2)
+			return h
+		},
+		newLarge: func() Backend {
+			h, _ := NewSpatialHash(50, 50)
+			return h
+		},
+	},
+}
+
+func TestBackendTree(t *testing.T) {
+	_, err := NewBackendTree(nil)
+	assert.Equal(t, oakerr.NilInput{InputName: "b"}, err)
+	_, err = NewHashTree(0, 1)
+	assert.Equal(t, oakerr.InvalidInput{InputName: "cellW"}, err)
+	_, err = NewHashTree(1, -1)
+	assert.Equal(t, oakerr.InvalidInput{InputName: "cellH"}, err)
+
+	for _, tb := range testBackends {
+		t.Run(tb.name, func(t *testing.T) {
+			tree, err := NewBackendTree(tb.new())
+			assert.Nil(t, err)
+			s1 := NewUnassignedSpace(0, 0, 3, 3)
+			s2 := NewUnassignedSpace(10, 10, 3, 3)
+			tree.Add(s1, s2)
+			assert.Len(t, tree.Hits(NewUnassignedSpace(1, 1, 1, 1)), 1)
+
+			// Move across several cells
+			assert.Nil(t, tree.UpdateSpace(20, 20, 3, 3, s1))
+			assert.Empty(t, tree.Hits(NewUnassignedSpace(1, 1, 1, 1)))
+			assert.Equal(t, []*Space{s1}, tree.Hits(NewUnassignedSpace(21, 21, 1, 1)))
+			// Move within a cell
+			assert.Nil(t, tree.ShiftSpace(.5, .5, s1))
+			assert.Equal(t, 20.5, s1.X())
+			assert.Equal(t, []*Space{s1}, tree.Hits(NewUnassignedSpace(23, 23, 1, 1)))
+			// Update spaces not yet in the tree
+			s3 := NewUnassignedSpace(0, 0, 1, 1)
+			assert.Nil(t, tree.UpdateSpace(-5, -5, 3, 3, s3))
+			assert.Equal(t, []*Space{s3}, tree.Hits(NewUnassignedSpace(-4, -4, 1, 1)))
+
+			assert.Equal(t, s2, tree.NearestNeighbor(floatgeom.Point3{9, 9, 0}))
+			near := tree.NearestNeighbors(4, floatgeom.Point3{9, 9, 0})
+			assert.Equal(t, []*Space{s2, s3, s1, nil}, near)
+
+			assert.ElementsMatch(t, []*Space{s1, s2, s3}, tree.Spaces())
+
+			// Searches may have infinite bounds
+			inf := math.Inf(1)
+			all := tree.SearchIntersect(floatgeom.NewRect3(-inf, -inf, -inf, inf, inf, inf))
+			assert.ElementsMatch(t, []*Space{s1, s2, s3}, all)
+			right := tree.SearchIntersect(floatgeom.NewRect3(5, -inf, -inf, inf, inf, inf))
+			assert.ElementsMatch(t, []*Space{s1, s2}, right)
+			below := tree.SearchIntersect(floatgeom.NewRect3(-inf, 15, -inf, inf, inf, inf))
+			assert.Equal(t, []*Space{s1}, below)
+			huge := tree.SearchIntersect(floatgeom.NewRect3(-1e300, -1e300, -1, 1e300, 1e300, 1))
+			assert.ElementsMatch(t, []*Space{s1, s2, s3}, huge)
+
+			assert.Equal(t, 2, tree.Remove(s1, s2))
+			assert.Equal(t, 0, tree.Remove(s1))
+			assert.Empty(t, tree.Hits(NewUnassignedSpace(21, 21, 1, 1)))
+
+			tree.Clear()
+			assert.Empty(t, tree.Hits(NewUnassignedSpace(-4, -4, 1, 1)))
+			assert.Nil(t, tree.NearestNeighbor(floatgeom.Point3{}))
+		})
+	}
+}
+
+func TestBackendTreeConcurrency(t *testing.T) {
+	for _, tb := range testBackends {
+		t.Run(tb.name, func(t *testing.T) {
+			tree, err := NewBackendTree(tb.newLarge())
+			assert.Nil(t, err)
+			sps := make([]*Space, 50)
+			for i := range sps {
+				sps[i] = NewUnassignedSpace(float64(i*10), 0, 5, 5)
+			}
+			tree.Add(sps...)
+			var wg sync.WaitGroup
+			// Spaces are moved across many cells while the tree is queried
+			for i := 0; i < 4; i++ {
+				wg.Add(2)
+				go func(i int) {
+					defer wg.Done()
+					for j := 0; j < 500; j++ {
+						sp := sps[(i*13+j)%len(sps)]
+						tree.UpdateSpace(float64(j%500), float64((i*j)%500), 5, 5, sp)
+					}
+				}(i)
+				go func(i int) {
+					defer wg.Done()
+					for j := 0; j < 500; j++ {
+						sp := sps[(i*7+j)%len(sps)]
+						tree.Hits(sp)
+						tree.HitLabel(sp, NilLabel)
+						tree.Contacts(sp)
+						tree.Sweep(sp, physics.NewVector(5, 5))
+						tree.RayHits(floatgeom.Point2{0, 0}, floatgeom.Point2{1, 1}, 500)
+						tree.NearestNeighbors(3, floatgeom.Point3{float64(j), float64(j), 0})
+						tree.SearchIntersect(NewRect(0, 0, 250, 250))
+						tree.Spaces()
+					}
+				}(i)
+			}
+			wg.Wait()
+			assert.Len(t, tree.Spaces(), len(sps))
+		})
+	}
+}
+
+func TestSpatialHashLargeSearch(t *testing.T) {
+	h, _ := NewSpatialHash(1, 1)
+	sps := []*Space{
+		NewUnassignedSpace(0, 0, 1, 1),
+		NewUnassignedSpace(500, 500, 1, 1),
+		NewUnassignedSpace(-1000, 2000, 5, 5),
+	}
+	for _, s := range sps {
+		h.Insert(s)
+	}
+	assert.Equal(t, 3, h.Len())
+	assert.Len(t, h.SearchIntersect(NewRect(-2000, -2000, 5000, 5000)), 3)
+	assert.Equal(t, sps[2], h.NearestNeighbor(floatgeom.Point3{-900, 1900, 0}))
+	assert.Empty(t, h.NearestNeighbors(0, floatgeom.Point3{}))
+	// Reinserting a space is the same as moving it
+	sps[0].Location = NewRect(3, 3, 1, 1)
+	h.Insert(sps[0])
+	assert.Equal(t, 3, h.Len())
+	assert.Empty(t, h.SearchIntersect(NewRect(0, 0, 1, 1)))
+}
@@ -0,0 +1,218 @@
+package collision
+
+import (
+	"math"
+	"sort"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/oakerr"
+)
+
+// A SpaceUpdate is a new location for a space, for use with UpdateSpaces.
+type SpaceUpdate struct {
+	Space    *Space
+	Location floatgeom.Rect3
+}
+
+// A BulkLoader is a Backend which can be filled with many spaces at once
+// faster than it can have them inserted one at a time.
+type BulkLoader interface {
+	// Load replaces the contents of the backend with sps.
+	Load(sps []*Space)
+}
+
+// A BatchUpdater is a Backend which can apply many updates at once faster
+// than it can apply them one at a time.
+type BatchUpdater interface {
+	// UpdateAll applies each update as Backend.Update would.
+	UpdateAll(updates []SpaceUpdate)
+}
+
+// NewBulkTree returns a new collision Tree backed by an Rtree, containing
+// the given spaces. The tree is built from all of the spaces at once, which
+// is faster and results in a better balanced tree than adding them one at a
+// time. See NewTree for the meaning of children.
+func NewBulkTree(sps []*Space, children ...int) (*Tree, error) {
+	t, err := NewTree(children...)
+	if err != nil {
+		return nil, err
+	}
+	t.Load(sps...)
+	return t, nil
+}
+
+// Load replaces the contents of the tree with the given spaces. If the
+// tree's backend is a BulkLoader, the spaces are loaded all at once.
+func (t *Tree) Load(sps ...*Space) {
+	t.Lock()
+	ws := t.watchers
+	if bl, ok := t.Backend.(BulkLoader); ok {
+		bl.Load(sps)
+	} else {
+		t.Backend.Clear()
+		for _, sp := range sps {
+			if sp != nil {
+				t.Insert(sp)
+			}
+		}
+	}
+	t.Unlock()
+	if ws != nil {
+		changes := []Change{{Kind: Cleared}}
+		for _, sp := range sps {
+			if sp != nil {
+				changes = append(changes, Change{Kind: Added, Space: sp, To: sp.Location})
+			}
+		}
+		notify(ws, changes)
+	}
+}
+
+// UpdateSpaces moves each of the given spaces to its new location, as
+// UpdateSpaceRect would, while locking the tree only once. If the tree's
+// backend is a BatchUpdater, the updates are applied all at once. If any
+// update has a nil space, no updates are applied.
+func (t *Tree) UpdateSpaces(updates ...SpaceUpdate) error {
+	for _, u := range updates {
+		if u.Space == nil {
+			return oakerr.NilInput{InputName: "updates.Space"}
+		}
+	}
+	var changes []Change
+	t.Lock()
+	ws := t.watchers
+	if ws != nil {
+		changes = make([]Change, len(updates))
+		for i, u := range updates {
+			changes[i] = Change{Kind: Moved, Space: u.Space, From: u.Space.Location, To: u.Location}
+		}
+	}
+	if bu, ok := t.Backend.(BatchUpdater); ok {
+		bu.UpdateAll(updates)
+	} else {
+		for _, u := range updates {
+			t.Update(u.Space, u.Location)
+		}
+	}
+	t.Unlock()
+	notify(ws, changes)
+	return nil
+}
+
+// Load satisfies BulkLoader. The tree is built with the Sort-Tile-Recursive
+// algorithm: spaces are sorted into vertical slices by x, then each slice is
+// sorted by y and packed into leaves, and the leaves are packed the same way
+// into each level above them.
+//
+// Implemented per "STR: A Simple and Efficient Algorithm for R-Tree
+// Packing" by S. Leutenegger, M. Lopez and J. Edgington, ICDE, 1997.
+func (tree *Rtree) Load(sps []*Space) {
+	*tree = *newTree(tree.MinChildren, tree.MaxChildren)
+	entries := make([]entry, 0, len(sps))
+	for _, sp := range sps {
+		if sp != nil {
+			entries = append(entries, entry{bb: sp.Location, obj: sp})
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+	level := 1
+	nodes := tree.strPack(entries, level)
+	for len(nodes) > 1 {
+		level++
+		parents := make([]entry, len(nodes))
+		for i, n := range nodes {
+			parents[i] = entry{bb: n.computeBoundingBox(), child: n}
+		}
+		nodes = tree.strPack(parents, level)
+	}
+	tree.root = nodes[0]
+	tree.height = level
+	tree.size = len(entries)
+}
+
+// strPack packs entries into nodes at the given level.
+func (tree *Rtree) strPack(entries []entry, level int) []*node {
+	max := tree.MaxChildren
+	nodeCount := ceilDiv(len(entries), max)
+	sliceCount := int(math.Ceil(math.Sqrt(float64(nodeCount))))
+	sliceSize := sliceCount * max
+
+	sortByMidpoint(entries, 0)
+	nodes := make([]*node, 0, nodeCount)
+	for start := 0; start < len(entries); start += sliceSize {
+		end := start + sliceSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		slice := entries[start:end]
+		sortByMidpoint(slice, 1)
+		// Spread the slice evenly across its nodes, so that no node is left
+		// with only a few entries
+		chunks := ceilDiv(len(slice), max)
+		for i := 0; i < chunks; i++ {
+			chunk := slice[i*len(slice)/chunks : (i+1)*len(slice)/chunks]
+			n := &node{
+				leaf:    level == 1,
+				level:   level,
+				entries: make([]entry, len(chunk), max),
+			}
+			copy(n.entries, chunk)
+			for _, e := range n.entries {
+				if e.child != nil {
+					e.child.parent = n
+				}
+			}
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+func sortByMidpoint(entries []entry, dim int) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].bb.Midpoint(dim) < entries[j].bb.Midpoint(dim)
+	})
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+// UpdateAll satisfies BatchUpdater. When enough of the tree is being
+// updated, the tree is rebuilt as Load would instead of updating each space.
+func (tree *Rtree) UpdateAll(updates []SpaceUpdate) {
+	// Rebuilding costs about as much as updating a quarter of the tree
+	if len(updates)*4 < tree.size {
+		for _, u := range updates {
+			tree.Update(u.Space, u.Location)
+		}
+		return
+	}
+	sps := tree.Spaces()
+	inTree := make(map[*Space]bool, len(sps))
+	for _, sp := range sps {
+		inTree[sp] = true
+	}
+	for _, u := range updates {
+		u.Space.Location = u.Location
+		if !inTree[u.Space] {
+			inTree[u.Space] = true
+			sps = append(sps, u.Space)
+		}
+	}
+	tree.Load(sps)
+}
+
+// spaces appends all spaces under n to sps.
+func (tree *Rtree) spaces(n *node, sps []*Space) []*Space {
+	for _, e := range n.entries {
+		if n.leaf {
+			sps = append(sps, e.obj)
+		} else {
+			sps = tree.spaces(e.child, sps)
+		}
+	}
+	return sps
+}
@@ -0,0 +1,114 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/oakerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func checkNodeSizes(t *testing.T, rt *Rtree, n *node) {
+	if len(n.entries) > rt.MaxChildren {
+		t.Errorf("node at level %d has %d entries", n.level, len(n.entries))
+	}
+	if n.leaf {
+		return
+	}
+	for _, e := range n.entries {
+		if !e.bb.ContainsRect(e.child.computeBoundingBox()) {
+			t.Errorf("entry does not bound its child")
+		}
+		checkNodeSizes(t, rt, e.child)
+	}
+}
+
+func bruteIntersect(sps []*Space, s *Space) int {
+	count := 0
+	for _, sp := range sps {
+		if sp.Location.Intersects(s.Location) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestNewBulkTree(t *testing.T) {
+	_, err := NewBulkTree(nil, 10, 5)
+	assert.NotNil(t, err)
+
+	tree, err := NewBulkTree(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, tree.Hits(NewUnassignedSpace(0, 0, 10000, 10000)))
+
+	sps := make([]*Space, 5000)
+	for i := range sps {
+		sps[i] = randomSpace()
+	}
+	tree, err = NewBulkTree(append(sps, nil), 4, 10)
+	assert.Nil(t, err)
+	rt := tree.Backend.(*Rtree)
+	assert.Equal(t, len(sps), rt.size)
+	assert.Equal(t, rt.root.level, rt.height)
+	verify(t, rt.root)
+	checkNodeSizes(t, rt, rt.root)
+
+	for i := 0; i < 50; i++ {
+		s := randomSpace()
+		assert.Equal(t, bruteIntersect(sps, s), len(tree.Hits(s)))
+	}
+
+	// Loaded trees can still be modified as normal
+	extra := NewUnassignedSpace(-100, -100, 5, 5)
+	tree.Add(extra)
+	assert.Len(t, tree.Hits(NewUnassignedSpace(-99, -99, 1, 1)), 1)
+	for _, sp := range sps[:2500] {
+		assert.Equal(t, 1, tree.Remove(sp))
+	}
+	verify(t, rt.root)
+	assert.Equal(t, 2501, rt.size)
+}
+
+func TestUpdateSpaces(t *testing.T) {
+	for _, tb := range testBackends {
+		t.Run(tb.name, func(t *testing.T) {
+			tree, _ := NewBackendTree(tb.newLarge())
+			sps := make([]*Space, 200)
+			for i := range sps {
+				sps[i] = randomSpace()
+			}
+			tree.Add(sps...)
+
+			assert.Equal(t, oakerr.NilInput{InputName: "updates.Space"},
+				tree.UpdateSpaces(SpaceUpdate{Space: sps[0]}, SpaceUpdate{}))
+
+			// A few updates, then most of the tree at once
+			for _, count := range []int{10, 150} {
+				updates := make([]SpaceUpdate, count)
+				for i := range updates {
+					updates[i] = SpaceUpdate{
+						Space:    sps[i],
+						Location: randomSpace().Location,
+					}
+				}
+				assert.Nil(t, tree.UpdateSpaces(updates...))
+				for _, u := range updates {
+					assert.Equal(t, u.Location, u.Space.Location)
+				}
+				for i := 0; i < 20; i++ {
+					s := randomSpace()
+					assert.Equal(t, bruteIntersect(sps, s), len(tree.Hits(s)))
+				}
+			}
+
+			// Spaces not yet in the tree are added
+			added := NewUnassignedSpace(0, 0, 1, 1)
+			updates := []SpaceUpdate{{Space: added, Location: NewRect(-50, -50, 5, 5)}}
+			for _, sp := range sps {
+				updates = append(updates, SpaceUpdate{Space: sp, Location: sp.Location})
+			}
+			assert.Nil(t, tree.UpdateSpaces(updates...))
+			assert.Equal(t, []*Space{added}, tree.Hits(NewUnassignedSpace(-49, -49, 1, 1)))
+			assert.Equal(t, len(sps)+1, tree.Remove(append(sps, added)...))
+		})
+	}
+}
@@ -52,6 +52,12 @@ func HitLabel(sp *Space, labels ...Label) *Space {
 	return DefTree.HitLabel(sp, labels...)
 }
 
+// Contacts returns the spaces sp hits in the default rtree, along with
+// their minimum translation vectors
+func Contacts(sp *Space, fs ...Filter) []Contact {
+	return DefTree.Contacts(sp, fs...)
+}
+
 // Update updates this space with the default rtree
 func (s *Space) Update(x, y, w, h float64) error {
 	return DefTree.UpdateSpace(x, y, w, h, s)
@@ -10,10 +10,8 @@ type Filter func([]*Space) []*Space
 func FirstLabel(ls ...Label) Filter {
 	return func(sps []*Space) []*Space {
 		for _, s := range sps {
-			for _, l := range ls {
-				if s.Label == l {
-					return []*Space{s}
-				}
+			if s.HasLabel(ls...) {
+				return []*Space{s}
 			}
 		}
 		return []*Space{}
@@ -59,23 +57,13 @@ func WithoutCIDs(cids ...event.CID) Filter {
 // WithLabels will only return spaces with a label in the input
 func WithLabels(ls ...Label) Filter {
 	return With(func(s *Space) bool {
-		for _, l := range ls {
-			if s.Label == l {
-				return true
-			}
-		}
-		return false
+		return s.HasLabel(ls...)
 	})
 }
 
 // WithoutLabels will return no spaces with a label in the input
 func WithoutLabels(ls ...Label) Filter {
-	return With(func(s *Space) bool {
-		for _, l := range ls {
-			if s.Label == l {
-				return false
-			}
-		}
-		return true
+	return Without(func(s *Space) bool {
+		return s.HasLabel(ls...)
 	})
 }
@@ -0,0 +1,92 @@
+package collision
+
+import "sync"
+
+// A Matrix declares which pairs of labels can collide with each other.
+// When a tree has a matrix, its queries will never return spaces whose
+// labels cannot collide with the label of the space being queried for.
+// A nil Matrix lets all labels collide.
+type Matrix struct {
+	mutex sync.RWMutex
+	// pairs stores pairs which differ from the default
+	pairs    map[labelPair]bool
+	collides bool
+}
+
+// labelPair is an unordered pair of labels, stored smallest first
+type labelPair struct {
+	a, b Label
+}
+
+func newLabelPair(a, b Label) labelPair {
+	if a > b {
+		a, b = b, a
+	}
+	return labelPair{a, b}
+}
+
+// NewMatrix returns a Matrix where all pairs of labels collide with each
+// other unless set otherwise if collides is true, or where no pairs collide
+// unless set otherwise if collides is false.
+func NewMatrix(collides bool) *Matrix {
+	return &Matrix{
+		pairs:    make(map[labelPair]bool),
+		collides: collides,
+	}
+}
+
+// Set sets whether spaces labeled a and b can collide with each other.
+// Collision is symmetric; setting (a, b) also sets (b, a).
+func (m *Matrix) Set(a, b Label, collides bool) {
+	m.mutex.Lock()
+	if collides == m.collides {
+		delete(m.pairs, newLabelPair(a, b))
+	} else {
+		m.pairs[newLabelPair(a, b)] = collides
+	}
+	m.mutex.Unlock()
+}
+
+// Collides returns whether spaces labeled a and b can collide with each
+// other.
+func (m *Matrix) Collides(a, b Label) bool {
+	if m == nil {
+		return true
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if c, ok := m.pairs[newLabelPair(a, b)]; ok {
+		return c
+	}
+	return m.collides
+}
+
+// filter removes all spaces that cannot collide with l from sps. The
+// returned slice reuses sps.
+func (m *Matrix) filter(l Label, sps []*Space) []*Space {
+	if m == nil {
+		return sps
+	}
+	out := sps[:0]
+	for _, s := range sps {
+		if s == nil || m.Collides(l, s.Label) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// SetMatrix sets the collision matrix for this tree. A nil matrix lets all
+// labels collide.
+func (t *Tree) SetMatrix(m *Matrix) {
+	t.Lock()
+	t.matrix = m
+	t.Unlock()
+}
+
+// Matrix returns the collision matrix of this tree, which may be nil.
+func (t *Tree) Matrix() *Matrix {
+	t.RLock()
+	defer t.RUnlock()
+	return t.matrix
+}
@@ -0,0 +1,67 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/physics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrix(t *testing.T) {
+	var m *Matrix
+	assert.True(t, m.Collides(1, 2))
+
+	m = NewMatrix(true)
+	assert.True(t, m.Collides(1, 2))
+	m.Set(1, 2, false)
+	assert.False(t, m.Collides(1, 2))
+	assert.False(t, m.Collides(2, 1))
+	assert.True(t, m.Collides(1, 1))
+	m.Set(2, 1, true)
+	assert.True(t, m.Collides(1, 2))
+
+	m = NewMatrix(false)
+	assert.False(t, m.Collides(1, 2))
+	m.Set(1, 2, true)
+	assert.True(t, m.Collides(2, 1))
+	assert.False(t, m.Collides(1, 1))
+}
+
+func TestTreeMatrix(t *testing.T) {
+	const (
+		player Label = iota
+		enemy
+		bullet
+	)
+	tree, _ := NewTree()
+	assert.Nil(t, tree.Matrix())
+
+	pl := NewLabeledSpace(0, 0, 10, 10, player)
+	en := NewLabeledSpace(5, 5, 10, 10, enemy)
+	bl := NewLabeledSpace(2, 2, 2, 2, bullet)
+	tree.Add(pl, en, bl)
+	assert.Len(t, tree.Hits(pl), 2)
+
+	m := NewMatrix(true)
+	m.Set(player, bullet, false)
+	tree.SetMatrix(m)
+	assert.Equal(t, m, tree.Matrix())
+
+	assert.Equal(t, []*Space{en}, tree.Hits(pl))
+	assert.Nil(t, tree.HitLabel(pl, bullet))
+	assert.Equal(t, en, tree.HitLabel(pl, enemy))
+	for _, s := range tree.Hit(pl) {
+		assert.NotEqual(t, bl, s)
+	}
+	assert.Len(t, tree.Contacts(pl), 1)
+	assert.Empty(t, tree.Hits(bl))
+
+	_, ok := tree.Sweep(NewLabeledSpace(-20, 0, 2, 1, bullet), physics.NewVector(40, 0))
+	assert.False(t, ok)
+	hit, ok := tree.Sweep(NewLabeledSpace(-20, 6, 2, 2, bullet), physics.NewVector(40, 0))
+	assert.True(t, ok)
+	assert.Equal(t, en, hit.Space)
+
+	tree.SetMatrix(nil)
+	assert.Len(t, tree.Hits(pl), 2)
+}
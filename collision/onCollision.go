@@ -27,7 +27,9 @@ type collisionPhase interface {
 
 // PhaseCollision binds to the entity behind the space's CID so that it will
 // receive CollisionStart and CollisionStop events, appropriately when
-// entities begin to collide or stop colliding with the space.
+// entities begin to collide or stop colliding with the space. Labels
+// which the tree's Matrix says cannot collide with the space's label
+// are never reported.
 func PhaseCollision(s *Space, trees ...*Tree) error {
 	switch t := event.GetEntity(int(s.CID)).(type) {
 	case collisionPhase:
@@ -51,6 +53,11 @@ const (
 	Stop  = "CollisionStop"
 )
 
+func init() {
+	event.RegisterPayload(Start, Label(0))
+	event.RegisterPayload(Stop, Label(0))
+}
+
 func phaseCollisionEnter(id int, nothing interface{}) int {
 	e := event.GetEntity(id).(collisionPhase)
 	oc := e.getCollisionPhase()
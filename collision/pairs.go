@@ -0,0 +1,152 @@
+package collision
+
+import (
+	"sync"
+
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/physics"
+)
+
+// PairEnter/Stay/Exit: when two spaces in a tracked tree start overlapping,
+// keep overlapping, or stop overlapping. Each event is triggered on the
+// CIDs of both spaces.
+// Payload: (PairEvent) the two spaces and how they overlap
+const (
+	PairEnter = "CollisionPairEnter"
+	PairStay  = "CollisionPairStay"
+	PairExit  = "CollisionPairExit"
+)
+
+func init() {
+	event.RegisterPayload(PairEnter, PairEvent{})
+	event.RegisterPayload(PairStay, PairEvent{})
+	event.RegisterPayload(PairExit, PairEvent{})
+}
+
+// A PairEvent is the payload of pair events. Space belongs to the entity
+// the event was triggered on, and Other is the space it overlaps.
+type PairEvent struct {
+	Space    *Space
+	Other    *Space
+	CID      event.CID
+	OtherCID event.CID
+	// Overlap is the minimum translation vector which would move Space out
+	// of Other. It is zero for PairExit events.
+	Overlap physics.Vector
+}
+
+// spacePair is a pair of spaces, in the order they were first found
+type spacePair struct {
+	a, b *Space
+}
+
+// A PairTracker finds which pairs of spaces in a tree overlap each frame,
+// and triggers PairEnter, PairStay and PairExit events on the spaces'
+// CIDs as pairs start, keep and stop overlapping. Spaces are only paired
+// as Hits would pair them, so spaces whose labels cannot collide by the
+// tree's Matrix are never paired. Spaces with a CID of 0 are paired, but
+// no events are triggered for them.
+type PairTracker struct {
+	tree *Tree
+	sync.Mutex
+	touching map[spacePair]bool
+	stop     func()
+}
+
+// NewPairTracker returns a PairTracker for the given tree, or for DefTree
+// if tree is nil. The tracker does nothing until Update is called, see
+// TrackPairs.
+func NewPairTracker(tree *Tree) *PairTracker {
+	if tree == nil {
+		tree = DefTree
+	}
+	return &PairTracker{
+		tree:     tree,
+		touching: make(map[spacePair]bool),
+	}
+}
+
+// TrackPairs returns a PairTracker for the given tree, or for DefTree if
+// tree is nil, which updates at the start of every frame until stopped.
+func TrackPairs(tree *Tree) *PairTracker {
+	pt := NewPairTracker(tree)
+	pt.stop = event.EveryFrame(pt.Update)
+	return pt
+}
+
+// Stop stops a PairTracker made with TrackPairs from updating. Pairs which
+// were overlapping do not receive PairExit events.
+func (pt *PairTracker) Stop() {
+	if pt.stop != nil {
+		pt.stop()
+	}
+}
+
+// Update finds every overlapping pair of spaces in the tree in a single
+// pass and triggers the events for each pair which started, kept or
+// stopped overlapping since the last update. Removing a space from the
+// tree stops it overlapping everything.
+func (pt *PairTracker) Update() {
+	pt.Lock()
+	defer pt.Unlock()
+	sps := pt.tree.Spaces()
+	index := make(map[*Space]int, len(sps))
+	for i, sp := range sps {
+		index[sp] = i
+	}
+	touching := make(map[spacePair]bool, len(pt.touching))
+	for i, sp := range sps {
+		for _, h := range pt.tree.Hits(sp) {
+			// Each pair is found from both of its spaces, and is only
+			// handled from the first
+			if j, ok := index[h]; !ok || j < i {
+				continue
+			}
+			p := spacePair{sp, h}
+			if pt.touching[spacePair{h, sp}] {
+				p = spacePair{h, sp}
+			}
+			touching[p] = true
+			if pt.touching[p] {
+				triggerPair(PairStay, p.a, p.b)
+			} else {
+				triggerPair(PairEnter, p.a, p.b)
+			}
+		}
+	}
+	for p := range pt.touching {
+		if !touching[p] {
+			triggerPair(PairExit, p.a, p.b)
+		}
+	}
+	pt.touching = touching
+}
+
+// triggerPair triggers a pair event on the CIDs of both spaces.
+func triggerPair(eventName string, a, b *Space) {
+	var ab, ba physics.Vector
+	if eventName == PairExit {
+		ab, ba = physics.NewVector(0, 0), physics.NewVector(0, 0)
+	} else {
+		ab, _ = a.MTV(b)
+		ba = ab.Copy().Scale(-1)
+	}
+	if a.CID != 0 {
+		a.CID.Trigger(eventName, PairEvent{
+			Space:    a,
+			Other:    b,
+			CID:      a.CID,
+			OtherCID: b.CID,
+			Overlap:  ab,
+		})
+	}
+	if b.CID != 0 {
+		b.CID.Trigger(eventName, PairEvent{
+			Space:    b,
+			Other:    a,
+			CID:      b.CID,
+			OtherCID: a.CID,
+			Overlap:  ba,
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairTracker(t *testing.T) {
+	event.DefaultBus.SetSynchronous(true)
+	defer event.DefaultBus.SetSynchronous(false)
+
+	tree, _ := NewTree()
+	cid1 := (&cphase{}).Init()
+	cid2 := (&cphase{}).Init()
+	s1 := NewSpace(0, 0, 10, 10, cid1)
+	s2 := NewSpace(20, 0, 10, 10, cid2)
+	// Spaces without CIDs are paired, without their own events
+	s3 := NewUnassignedSpace(15, 5, 10, 10)
+	tree.Add(s1, s2, s3)
+
+	var got []string
+	var last PairEvent
+	for _, ev := range []string{PairEnter, PairStay, PairExit} {
+		ev := ev
+		for _, cid := range []event.CID{cid1, cid2} {
+			cid.Bind(func(id int, data interface{}) int {
+				pe := data.(PairEvent)
+				got = append(got, ev)
+				if int(pe.CID) == id && pe.Other == s1 {
+					last = pe
+				}
+				return 0
+			}, ev)
+		}
+	}
+	assert.Nil(t, event.Flush())
+
+	pt := NewPairTracker(tree)
+	pt.Update()
+	assert.Equal(t, []string{PairEnter}, got)
+
+	got = nil
+	tree.UpdateSpace(8, 0, 10, 10, s2)
+	pt.Update()
+	assert.ElementsMatch(t, []string{PairEnter, PairEnter, PairStay}, got)
+	assert.Equal(t, s2, last.Space)
+	assert.Equal(t, cid2, last.CID)
+	assert.Equal(t, cid1, last.OtherCID)
+	assert.Equal(t, 2.0, last.Overlap.X())
+
+	got = nil
+	pt.Update()
+	assert.ElementsMatch(t, []string{PairStay, PairStay, PairStay}, got)
+
+	got = nil
+	tree.Remove(s3)
+	tree.UpdateSpace(5, 0, 10, 10, s2)
+	pt.Update()
+	assert.ElementsMatch(t, []string{PairStay, PairStay, PairExit}, got)
+	assert.Equal(t, 5.0, last.Overlap.X())
+
+	// Labels which cannot collide are never paired
+	m := NewMatrix(true)
+	m.Set(NilLabel, NilLabel, false)
+	tree.SetMatrix(m)
+	got = nil
+	pt.Update()
+	assert.ElementsMatch(t, []string{PairExit, PairExit}, got)
+	assert.Equal(t, 0.0, last.Overlap.X())
+}
+
+func TestTrackPairs(t *testing.T) {
+	event.DefaultBus.SetSynchronous(true)
+	defer event.DefaultBus.SetSynchronous(false)
+
+	tree, _ := NewTree()
+	cid := (&cphase{}).Init()
+	tree.Add(NewSpace(0, 0, 10, 10, cid), NewUnassignedSpace(5, 5, 10, 10))
+	entered := 0
+	cid.Bind(func(int, interface{}) int {
+		entered++
+		return 0
+	}, PairEnter)
+	pt := TrackPairs(tree)
+	assert.Nil(t, event.Flush())
+	<-event.TriggerBack(event.Enter, 0)
+	assert.Equal(t, 1, entered)
+	pt.Stop()
+	tree.Clear()
+	tree.Add(NewSpace(0, 0, 10, 10, cid), NewUnassignedSpace(5, 5, 10, 10))
+	<-event.TriggerBack(event.Enter, 0)
+	assert.Equal(t, 1, entered)
+}
@@ -0,0 +1,232 @@
+package path
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/alg/intgeom"
+)
+
+// A Diagonal is a rule for when paths may move diagonally between cells.
+type Diagonal int
+
+// Diagonal values
+const (
+	// DiagonalNoCorners allows moving diagonally only when both cells
+	// beside the move are open, so paths never cut across the corners of
+	// blocked cells.
+	DiagonalNoCorners Diagonal = iota
+	// DiagonalOneCorner allows moving diagonally when at least one of the
+	// cells beside the move is open.
+	DiagonalOneCorner
+	// DiagonalAlways allows moving diagonally between any two open cells,
+	// even between two blocked cells.
+	DiagonalAlways
+	// DiagonalNever only allows moving horizontally and vertically.
+	DiagonalNever
+)
+
+var directions = [8]intgeom.Point2{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// AStar returns the shortest path between the cells containing start and
+// goal, moving between cells as diag allows. Moving to an adjacent cell
+// costs 1, and moving to a diagonal cell costs √2. The path is returned as
+// the centers of the cells it begins, turns and ends in. If either point is
+// outside of the grid or in a blocked cell, or if there is no path between
+// them, false is returned.
+func (g *Grid) AStar(start, goal floatgeom.Point2, diag Diagonal) ([]floatgeom.Point2, bool) {
+	return g.find(start, goal, diag, (*search).neighbors)
+}
+
+// A search is the state of one path finding query on a grid.
+type search struct {
+	g      *Grid
+	diag   Diagonal
+	goal   intgeom.Point2
+	cost   []float64
+	parent []int
+	closed []bool
+	open   nodeHeap
+}
+
+// A node is an entry in a search's open set, where i is the index of a
+// cell or triangle.
+type node struct {
+	i int
+	f float64
+}
+
+type nodeHeap []node
+
+func (h nodeHeap) Len() int            { return len(h) }
+func (h nodeHeap) Less(i, j int) bool  { return h[i].f < h[j].f }
+func (h nodeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x interface{}) { *h = append(*h, x.(node)) }
+func (h *nodeHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// find runs an A* search, where successors returns the cells which can be
+// moved to directly from a cell.
+func (g *Grid) find(start, goal floatgeom.Point2, diag Diagonal,
+	successors func(*search, intgeom.Point2) []intgeom.Point2) ([]floatgeom.Point2, bool) {
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.refresh()
+	s, ok := g.cellPair(start, goal)
+	if !ok {
+		return nil, false
+	}
+	sc := s[0]
+	srch := &search{
+		g:      g,
+		diag:   diag,
+		goal:   s[1],
+		cost:   make([]float64, g.w*g.h),
+		parent: make([]int, g.w*g.h),
+		closed: make([]bool, g.w*g.h),
+	}
+	for i := range srch.cost {
+		srch.cost[i] = math.Inf(1)
+		srch.parent[i] = -1
+	}
+	srch.cost[g.index(sc)] = 0
+	heap.Push(&srch.open, node{i: g.index(sc), f: srch.heuristic(sc)})
+	for srch.open.Len() > 0 {
+		i := heap.Pop(&srch.open).(node).i
+		if srch.closed[i] {
+			continue
+		}
+		srch.closed[i] = true
+		c := g.cellAt(i)
+		if c == srch.goal {
+			return srch.path(), true
+		}
+		for _, next := range successors(srch, c) {
+			j := g.index(next)
+			if srch.closed[j] {
+				continue
+			}
+			cost := srch.cost[i] + octile(c, next)
+			if cost < srch.cost[j] {
+				srch.cost[j] = cost
+				srch.parent[j] = i
+				heap.Push(&srch.open, node{i: j, f: cost + srch.heuristic(next)})
+			}
+		}
+	}
+	return nil, false
+}
+
+// cellPair returns the open cells containing start and goal.
+func (g *Grid) cellPair(start, goal floatgeom.Point2) ([2]intgeom.Point2, bool) {
+	sc, ok := g.Cell(start)
+	if !ok || g.isBlocked(sc.X(), sc.Y()) {
+		return [2]intgeom.Point2{}, false
+	}
+	gc, ok := g.Cell(goal)
+	if !ok || g.isBlocked(gc.X(), gc.Y()) {
+		return [2]intgeom.Point2{}, false
+	}
+	return [2]intgeom.Point2{sc, gc}, true
+}
+
+func (g *Grid) index(c intgeom.Point2) int {
+	return c.Y()*g.w + c.X()
+}
+
+func (g *Grid) cellAt(i int) intgeom.Point2 {
+	return intgeom.Point2{i % g.w, i / g.w}
+}
+
+// octile returns the cost of the shortest path between two cells on an
+// empty grid where diagonal moves are allowed.
+func octile(a, b intgeom.Point2) float64 {
+	dx := math.Abs(float64(a.X() - b.X()))
+	dy := math.Abs(float64(a.Y() - b.Y()))
+	return dx + dy + (math.Sqrt2-2)*math.Min(dx, dy)
+}
+
+func (s *search) heuristic(c intgeom.Point2) float64 {
+	if s.diag == DiagonalNever {
+		return math.Abs(float64(c.X()-s.goal.X())) + math.Abs(float64(c.Y()-s.goal.Y()))
+	}
+	return octile(c, s.goal)
+}
+
+func (s *search) isOpen(x, y int) bool {
+	return !s.g.isBlocked(x, y)
+}
+
+// canMove returns whether (x, y) can be moved to directly from
+// (x-dx, y-dy), where dx and dy are each -1, 0 or 1.
+func (s *search) canMove(x, y, dx, dy int) bool {
+	if !s.isOpen(x, y) {
+		return false
+	}
+	if dx == 0 || dy == 0 {
+		return true
+	}
+	switch s.diag {
+	case DiagonalNever:
+		return false
+	case DiagonalNoCorners:
+		return s.isOpen(x-dx, y) && s.isOpen(x, y-dy)
+	case DiagonalOneCorner:
+		return s.isOpen(x-dx, y) || s.isOpen(x, y-dy)
+	}
+	return true
+}
+
+// neighbors returns the cells which can be moved to from c.
+func (s *search) neighbors(c intgeom.Point2) []intgeom.Point2 {
+	out := make([]intgeom.Point2, 0, 8)
+	for _, d := range directions {
+		next := c.Add(d)
+		if s.canMove(next.X(), next.Y(), d.X(), d.Y()) {
+			out = append(out, next)
+		}
+	}
+	return out
+}
+
+// path returns the path found to the goal, dropping cells which do not
+// change the path's direction.
+func (s *search) path() []floatgeom.Point2 {
+	var cells []intgeom.Point2
+	for i := s.g.index(s.goal); i != -1; i = s.parent[i] {
+		cells = append(cells, s.g.cellAt(i))
+	}
+	points := make([]floatgeom.Point2, 0, len(cells))
+	for i := len(cells) - 1; i >= 0; i-- {
+		if i > 0 && i < len(cells)-1 &&
+			direction(cells[i+1], cells[i]) == direction(cells[i], cells[i-1]) {
+			continue
+		}
+		points = append(points, s.g.CellCenter(cells[i]))
+	}
+	return points
+}
+
+// direction returns the sign of each axis of b-a.
+func direction(a, b intgeom.Point2) intgeom.Point2 {
+	return intgeom.Point2{sign(b.X() - a.X()), sign(b.Y() - a.Y())}
+}
+
+func sign(i int) int {
+	if i < 0 {
+		return -1
+	}
+	if i > 0 {
+		return 1
+	}
+	return 0
+}
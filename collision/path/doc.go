@@ -0,0 +1,3 @@
+// Package path finds paths through grids and navigation meshes built from
+// collision trees
+package path
@@ -0,0 +1,222 @@
+package path
+
+import (
+	"math"
+	"sync"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/alg/intgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/oakerr"
+)
+
+// A Grid divides an area of a collision tree into cells, each of which is
+// blocked if a space with a blocking label overlaps it. Grids watch their
+// trees, and when blocking spaces are added, moved or removed through the
+// tree's methods only the cells those spaces overlapped or now overlap are
+// sampled again, the next time the grid is used.
+//
+// Spaces block cells with their rectangles, ignoring any shapes they have.
+type Grid struct {
+	tree         *collision.Tree
+	labels       []collision.Label
+	bounds       floatgeom.Rect2
+	cellW, cellH float64
+	w, h         int
+
+	mutex   sync.Mutex
+	blocked []bool
+	// dirty stores ranges of cells which need to be sampled again
+	dirty []cellRange
+	stop  func()
+}
+
+// cellRange is an inclusive range of cells
+type cellRange struct {
+	min, max intgeom.Point2
+}
+
+// NewGrid returns a Grid covering bounds of the given tree with cells of
+// the given width and height. Spaces with one of the given labels block
+// the cells they overlap. If no labels are given, all spaces block. If tree
+// is nil, collision.DefTree is used. Grids should be closed when no longer
+// needed, so they stop watching their tree.
+func NewGrid(tree *collision.Tree, bounds floatgeom.Rect2, cellW, cellH float64, blocking ...collision.Label) (*Grid, error) {
+	if cellW <= 0 || math.IsInf(cellW, 0) || math.IsNaN(cellW) {
+		return nil, oakerr.InvalidInput{InputName: "cellW"}
+	}
+	if cellH <= 0 || math.IsInf(cellH, 0) || math.IsNaN(cellH) {
+		return nil, oakerr.InvalidInput{InputName: "cellH"}
+	}
+	if bounds.W() <= 0 || bounds.H() <= 0 {
+		return nil, oakerr.InvalidInput{InputName: "bounds"}
+	}
+	if tree == nil {
+		tree = collision.DefTree
+	}
+	g := &Grid{
+		tree:   tree,
+		labels: blocking,
+		bounds: bounds,
+		cellW:  cellW,
+		cellH:  cellH,
+		w:      int(math.Ceil(bounds.W() / cellW)),
+		h:      int(math.Ceil(bounds.H() / cellH)),
+	}
+	g.blocked = make([]bool, g.w*g.h)
+	g.dirty = []cellRange{{max: intgeom.Point2{g.w - 1, g.h - 1}}}
+	g.stop = tree.Watch(g.onChange)
+	return g, nil
+}
+
+// Close stops the grid from watching its tree. A closed grid can still be
+// searched, but will not notice changes to its tree.
+func (g *Grid) Close() {
+	g.stop()
+}
+
+// Size returns how many cells wide and tall the grid is.
+func (g *Grid) Size() (int, int) {
+	return g.w, g.h
+}
+
+// CellSize returns the width and height of the grid's cells.
+func (g *Grid) CellSize() (float64, float64) {
+	return g.cellW, g.cellH
+}
+
+// Bounds returns the area of the tree the grid covers. The grid's cells
+// may extend past the bottom and right of its bounds, if they do not
+// evenly divide them.
+func (g *Grid) Bounds() floatgeom.Rect2 {
+	return g.bounds
+}
+
+// Cell returns the cell containing p, and whether that cell is within the
+// grid.
+func (g *Grid) Cell(p floatgeom.Point2) (intgeom.Point2, bool) {
+	c := intgeom.Point2{
+		int(math.Floor((p.X() - g.bounds.Min.X()) / g.cellW)),
+		int(math.Floor((p.Y() - g.bounds.Min.Y()) / g.cellH)),
+	}
+	return c, g.inBounds(c.X(), c.Y())
+}
+
+// CellCenter returns the center of the given cell.
+func (g *Grid) CellCenter(c intgeom.Point2) floatgeom.Point2 {
+	return floatgeom.Point2{
+		g.bounds.Min.X() + (float64(c.X())+.5)*g.cellW,
+		g.bounds.Min.Y() + (float64(c.Y())+.5)*g.cellH,
+	}
+}
+
+// Blocked returns whether the given cell is blocked. Cells outside of the
+// grid are blocked.
+func (g *Grid) Blocked(c intgeom.Point2) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.refresh()
+	return g.isBlocked(c.X(), c.Y())
+}
+
+func (g *Grid) inBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.w && y < g.h
+}
+
+func (g *Grid) isBlocked(x, y int) bool {
+	return !g.inBounds(x, y) || g.blocked[y*g.w+x]
+}
+
+func (g *Grid) blocks(sp *collision.Space) bool {
+	return sp != nil && isBlocking(sp, g.labels)
+}
+
+func (g *Grid) onChange(c collision.Change) {
+	if c.Kind != collision.Cleared && !g.blocks(c.Space) {
+		return
+	}
+	g.mutex.Lock()
+	switch c.Kind {
+	case collision.Cleared:
+		g.dirty = append(g.dirty[:0], cellRange{max: intgeom.Point2{g.w - 1, g.h - 1}})
+	case collision.Added:
+		g.invalidate(c.To)
+	case collision.Removed:
+		g.invalidate(c.From)
+	case collision.Moved:
+		g.invalidate(c.From)
+		g.invalidate(c.To)
+	}
+	g.mutex.Unlock()
+}
+
+// invalidate marks the cells r overlaps to be sampled again.
+func (g *Grid) invalidate(r floatgeom.Rect3) {
+	if cr, ok := g.cellsOf(r); ok {
+		g.dirty = append(g.dirty, cr)
+	}
+}
+
+// cellsOf returns the range of cells r overlaps, and whether it overlaps
+// any. Cells which r only touches the edge of are not overlapped.
+func (g *Grid) cellsOf(r floatgeom.Rect3) (cellRange, bool) {
+	x0 := (r.Min.X() - g.bounds.Min.X()) / g.cellW
+	x1 := (r.Max.X() - g.bounds.Min.X()) / g.cellW
+	y0 := (r.Min.Y() - g.bounds.Min.Y()) / g.cellH
+	y1 := (r.Max.Y() - g.bounds.Min.Y()) / g.cellH
+	cr := cellRange{
+		min: intgeom.Point2{clamp(math.Floor(x0), g.w), clamp(math.Floor(y0), g.h)},
+		max: intgeom.Point2{clamp(math.Ceil(x1)-1, g.w), clamp(math.Ceil(y1)-1, g.h)},
+	}
+	if x1 <= 0 || y1 <= 0 || x0 >= float64(g.w) || y0 >= float64(g.h) ||
+		cr.min.X() > cr.max.X() || cr.min.Y() > cr.max.Y() {
+		return cr, false
+	}
+	return cr, true
+}
+
+func clamp(f float64, n int) int {
+	if f < 0 {
+		return 0
+	}
+	if f > float64(n-1) {
+		return n - 1
+	}
+	return int(f)
+}
+
+// refresh samples all dirty cells from the tree. The grid must be locked.
+func (g *Grid) refresh() {
+	for _, cr := range g.dirty {
+		for y := cr.min.Y(); y <= cr.max.Y(); y++ {
+			for x := cr.min.X(); x <= cr.max.X(); x++ {
+				g.blocked[y*g.w+x] = false
+			}
+		}
+		area := floatgeom.NewRect3(
+			g.bounds.Min.X()+float64(cr.min.X())*g.cellW,
+			g.bounds.Min.Y()+float64(cr.min.Y())*g.cellH,
+			math.Inf(-1),
+			g.bounds.Min.X()+float64(cr.max.X()+1)*g.cellW,
+			g.bounds.Min.Y()+float64(cr.max.Y()+1)*g.cellH,
+			math.Inf(1),
+		)
+		for _, sp := range g.tree.SearchIntersect(area) {
+			if !g.blocks(sp) {
+				continue
+			}
+			spCells, ok := g.cellsOf(sp.Location)
+			if !ok {
+				continue
+			}
+			lo := spCells.min.GreaterOf(cr.min)
+			hi := spCells.max.LesserOf(cr.max)
+			for y := lo.Y(); y <= hi.Y(); y++ {
+				for x := lo.X(); x <= hi.X(); x++ {
+					g.blocked[y*g.w+x] = true
+				}
+			}
+		}
+	}
+	g.dirty = g.dirty[:0]
+}
@@ -0,0 +1,171 @@
+package path
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/alg/intgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	wall collision.Label = iota + 1
+	floor
+)
+
+func TestNewGrid(t *testing.T) {
+	tree, _ := collision.NewTree()
+	bounds := floatgeom.NewRect2(0, 0, 100, 50)
+	_, err := NewGrid(tree, bounds, 0, 10, wall)
+	assert.NotNil(t, err)
+	_, err = NewGrid(tree, bounds, 10, -1, wall)
+	assert.NotNil(t, err)
+	_, err = NewGrid(tree, floatgeom.Rect2{}, 10, 10, wall)
+	assert.NotNil(t, err)
+
+	g, err := NewGrid(tree, bounds, 30, 10, wall)
+	assert.Nil(t, err)
+	defer g.Close()
+	w, h := g.Size()
+	assert.Equal(t, 4, w)
+	assert.Equal(t, 5, h)
+	c, ok := g.Cell(floatgeom.Point2{35, 5})
+	assert.True(t, ok)
+	assert.Equal(t, intgeom.Point2{1, 0}, c)
+	_, ok = g.Cell(floatgeom.Point2{-1, 5})
+	assert.False(t, ok)
+	assert.Equal(t, floatgeom.Point2{45, 5}, g.CellCenter(c))
+}
+
+func TestGridInvalidation(t *testing.T) {
+	tree, _ := collision.NewTree()
+	// This space only touches the edges of cells, and blocks only the
+	// cells it covers
+	w1 := collision.NewLabeledSpace(10, 10, 20, 10, wall)
+	tree.Add(w1)
+	tree.Add(collision.NewLabeledSpace(50, 10, 10, 10, floor))
+
+	g, err := NewGrid(tree, floatgeom.NewRect2(0, 0, 100, 100), 10, 10, wall)
+	assert.Nil(t, err)
+	assert.True(t, g.Blocked(intgeom.Point2{1, 1}))
+	assert.True(t, g.Blocked(intgeom.Point2{2, 1}))
+	assert.False(t, g.Blocked(intgeom.Point2{3, 1}))
+	assert.False(t, g.Blocked(intgeom.Point2{1, 2}))
+	assert.False(t, g.Blocked(intgeom.Point2{5, 1}))
+	assert.True(t, g.Blocked(intgeom.Point2{-1, 0}))
+
+	tree.UpdateSpace(15, 45, 10, 10, w1)
+	assert.False(t, g.Blocked(intgeom.Point2{1, 1}))
+	assert.False(t, g.Blocked(intgeom.Point2{2, 1}))
+	assert.True(t, g.Blocked(intgeom.Point2{1, 4}))
+	assert.True(t, g.Blocked(intgeom.Point2{2, 5}))
+
+	w2 := collision.NewLabeledSpace(75, 75, 1, 1, wall)
+	tree.Add(w2)
+	assert.True(t, g.Blocked(intgeom.Point2{7, 7}))
+	tree.Remove(w2)
+	assert.False(t, g.Blocked(intgeom.Point2{7, 7}))
+
+	tree.UpdateLabel(wall, tree.NearestNeighbor(floatgeom.Point3{55, 15, 0}))
+	assert.True(t, g.Blocked(intgeom.Point2{5, 1}))
+
+	tree.Clear()
+	assert.False(t, g.Blocked(intgeom.Point2{5, 1}))
+	assert.False(t, g.Blocked(intgeom.Point2{1, 4}))
+
+	tree.Load(w1)
+	assert.True(t, g.Blocked(intgeom.Point2{1, 4}))
+
+	g.Close()
+	tree.Remove(w1)
+	assert.True(t, g.Blocked(intgeom.Point2{1, 4}))
+}
+
+func TestAStar(t *testing.T) {
+	tree, _ := collision.NewTree()
+	// A wall down the middle with a gap at the bottom
+	tree.Add(collision.NewLabeledSpace(40, 0, 10, 80, wall))
+	g, _ := NewGrid(tree, floatgeom.NewRect2(0, 0, 100, 100), 10, 10, wall)
+	defer g.Close()
+
+	path, ok := g.AStar(floatgeom.Point2{5, 5}, floatgeom.Point2{95, 5}, DiagonalNever)
+	assert.True(t, ok)
+	assert.Equal(t, 250.0, pathLength(path))
+
+	path, ok = g.AStar(floatgeom.Point2{35, 75}, floatgeom.Point2{55, 75}, DiagonalNoCorners)
+	assert.True(t, ok)
+	assert.Equal(t, []floatgeom.Point2{{35, 75}, {35, 85}, {55, 85}, {55, 75}}, path)
+
+	path, ok = g.AStar(floatgeom.Point2{35, 75}, floatgeom.Point2{55, 75}, DiagonalOneCorner)
+	assert.True(t, ok)
+	assert.Equal(t, []floatgeom.Point2{{35, 75}, {45, 85}, {55, 75}}, path)
+
+	path, ok = g.AStar(floatgeom.Point2{5, 5}, floatgeom.Point2{5, 5}, DiagonalAlways)
+	assert.True(t, ok)
+	assert.Equal(t, []floatgeom.Point2{{5, 5}}, path)
+
+	_, ok = g.AStar(floatgeom.Point2{5, 5}, floatgeom.Point2{45, 5}, DiagonalAlways)
+	assert.False(t, ok)
+	_, ok = g.AStar(floatgeom.Point2{5, 5}, floatgeom.Point2{105, 5}, DiagonalAlways)
+	assert.False(t, ok)
+
+	tree.Add(collision.NewLabeledSpace(40, 80, 10, 20, wall))
+	_, ok = g.AStar(floatgeom.Point2{5, 5}, floatgeom.Point2{95, 5}, DiagonalAlways)
+	assert.False(t, ok)
+}
+
+func TestDiagonalAlways(t *testing.T) {
+	tree, _ := collision.NewTree()
+	tree.Add(collision.NewLabeledSpace(10, 0, 10, 10, wall))
+	tree.Add(collision.NewLabeledSpace(0, 10, 10, 10, wall))
+	g, _ := NewGrid(tree, floatgeom.NewRect2(0, 0, 20, 20), 10, 10, wall)
+	defer g.Close()
+	for _, diag := range []Diagonal{DiagonalNoCorners, DiagonalOneCorner, DiagonalNever} {
+		_, ok := g.AStar(floatgeom.Point2{5, 5}, floatgeom.Point2{15, 15}, diag)
+		assert.False(t, ok)
+		_, ok = g.JPS(floatgeom.Point2{5, 5}, floatgeom.Point2{15, 15}, diag)
+		assert.False(t, ok)
+	}
+	path, ok := g.AStar(floatgeom.Point2{5, 5}, floatgeom.Point2{15, 15}, DiagonalAlways)
+	assert.True(t, ok)
+	assert.Len(t, path, 2)
+	path, ok = g.JPS(floatgeom.Point2{5, 5}, floatgeom.Point2{15, 15}, DiagonalAlways)
+	assert.True(t, ok)
+	assert.Len(t, path, 2)
+}
+
+func pathLength(path []floatgeom.Point2) float64 {
+	l := 0.0
+	for i := 1; i < len(path); i++ {
+		l += path[i].Distance(path[i-1])
+	}
+	return l
+}
+
+func TestJPSMatchesAStar(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		tree, _ := collision.NewTree()
+		for i := 0; i < 120; i++ {
+			tree.Add(collision.NewLabeledSpace(float64(rng.Intn(30)), float64(rng.Intn(30)), 1, 1, wall))
+		}
+		g, _ := NewGrid(tree, floatgeom.NewRect2(0, 0, 30, 30), 1, 1, wall)
+		for i := 0; i < 10; i++ {
+			start := floatgeom.Point2{float64(rng.Intn(30)) + .5, float64(rng.Intn(30)) + .5}
+			goal := floatgeom.Point2{float64(rng.Intn(30)) + .5, float64(rng.Intn(30)) + .5}
+			for _, diag := range []Diagonal{DiagonalNoCorners, DiagonalOneCorner, DiagonalAlways, DiagonalNever} {
+				aPath, aOK := g.AStar(start, goal, diag)
+				jPath, jOK := g.JPS(start, goal, diag)
+				assert.Equal(t, aOK, jOK, "%v %v %v", start, goal, diag)
+				assert.InDelta(t, pathLength(aPath), pathLength(jPath), 1e-9, "%v %v %v", start, goal, diag)
+				if jOK {
+					assert.Equal(t, start, jPath[0])
+					assert.Equal(t, goal, jPath[len(jPath)-1])
+				}
+			}
+		}
+		g.Close()
+	}
+}
@@ -0,0 +1,182 @@
+package path
+
+import (
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/alg/intgeom"
+)
+
+// JPS acts like AStar, returning a path of the same length, but searches
+// with jump point search. Instead of adding every neighbor of a cell to
+// the search, jump point search moves in straight lines from each cell
+// until reaching a cell where the path could need to turn, which makes it
+// much faster than AStar across large open areas.
+//
+// Implemented per "Online Graph Pruning for Pathfinding on Grid Maps" by
+// D. Harabor and A. Grastien, AAAI, 2011.
+func (g *Grid) JPS(start, goal floatgeom.Point2, diag Diagonal) ([]floatgeom.Point2, bool) {
+	return g.find(start, goal, diag, (*search).jumpSuccessors)
+}
+
+// jumpSuccessors returns the jump points reachable from c.
+func (s *search) jumpSuccessors(c intgeom.Point2) []intgeom.Point2 {
+	var candidates []intgeom.Point2
+	if p := s.parent[s.g.index(c)]; p == -1 {
+		candidates = s.neighbors(c)
+	} else {
+		d := direction(s.g.cellAt(p), c)
+		candidates = s.prunedNeighbors(c.X(), c.Y(), d.X(), d.Y())
+	}
+	out := candidates[:0]
+	for _, n := range candidates {
+		d := direction(c, n)
+		if jp, ok := s.jump(n.X(), n.Y(), d.X(), d.Y()); ok {
+			out = append(out, jp)
+		}
+	}
+	return out
+}
+
+// prunedNeighbors returns the neighbors of (x, y) which could be on a
+// shortest path that reached (x, y) moving in the direction (dx, dy).
+func (s *search) prunedNeighbors(x, y, dx, dy int) []intgeom.Point2 {
+	out := make([]intgeom.Point2, 0, 5)
+	add := func(nx, ny int) {
+		if s.canMove(nx, ny, nx-x, ny-y) {
+			out = append(out, intgeom.Point2{nx, ny})
+		}
+	}
+	open := s.isOpen
+	switch s.diag {
+	case DiagonalNever:
+		if dx != 0 {
+			add(x, y-1)
+			add(x, y+1)
+			add(x+dx, y)
+		} else {
+			add(x-1, y)
+			add(x+1, y)
+			add(x, y+dy)
+		}
+	case DiagonalNoCorners:
+		if dx != 0 && dy != 0 {
+			add(x, y+dy)
+			add(x+dx, y)
+			add(x+dx, y+dy)
+		} else if dx != 0 {
+			add(x+dx, y)
+			add(x+dx, y+1)
+			add(x+dx, y-1)
+			add(x, y+1)
+			add(x, y-1)
+		} else {
+			add(x, y+dy)
+			add(x+1, y+dy)
+			add(x-1, y+dy)
+			add(x+1, y)
+			add(x-1, y)
+		}
+	default:
+		if dx != 0 && dy != 0 {
+			add(x, y+dy)
+			add(x+dx, y)
+			add(x+dx, y+dy)
+			if !open(x-dx, y) {
+				add(x-dx, y+dy)
+			}
+			if !open(x, y-dy) {
+				add(x+dx, y-dy)
+			}
+		} else if dx != 0 {
+			add(x+dx, y)
+			if !open(x, y+1) {
+				add(x+dx, y+1)
+			}
+			if !open(x, y-1) {
+				add(x+dx, y-1)
+			}
+		} else {
+			add(x, y+dy)
+			if !open(x+1, y) {
+				add(x+1, y+dy)
+			}
+			if !open(x-1, y) {
+				add(x-1, y+dy)
+			}
+		}
+	}
+	return out
+}
+
+// jump moves from (x, y) in the direction (dx, dy), which (x, y) was
+// reached by moving in, until reaching the goal or a cell with a neighbor
+// which can only be reached optimally through that cell.
+func (s *search) jump(x, y, dx, dy int) (intgeom.Point2, bool) {
+	open := s.isOpen
+	for {
+		if !open(x, y) {
+			return intgeom.Point2{}, false
+		}
+		c := intgeom.Point2{x, y}
+		if c == s.goal {
+			return c, true
+		}
+		switch s.diag {
+		case DiagonalNever:
+			if dx != 0 {
+				if open(x, y-1) && !open(x-dx, y-1) || open(x, y+1) && !open(x-dx, y+1) {
+					return c, true
+				}
+			} else {
+				if open(x-1, y) && !open(x-1, y-dy) || open(x+1, y) && !open(x+1, y-dy) {
+					return c, true
+				}
+				// Vertical moves turn at cells where a horizontal move would
+				// reach a jump point
+				if s.jumps(x+1, y, 1, 0) || s.jumps(x-1, y, -1, 0) {
+					return c, true
+				}
+			}
+		case DiagonalNoCorners:
+			if dx != 0 && dy != 0 {
+				if s.jumps(x+dx, y, dx, 0) || s.jumps(x, y+dy, 0, dy) {
+					return c, true
+				}
+			} else if dx != 0 {
+				if open(x, y-1) && !open(x-dx, y-1) || open(x, y+1) && !open(x-dx, y+1) {
+					return c, true
+				}
+			} else {
+				if open(x-1, y) && !open(x-1, y-dy) || open(x+1, y) && !open(x+1, y-dy) {
+					return c, true
+				}
+			}
+		default:
+			if dx != 0 && dy != 0 {
+				if open(x-dx, y+dy) && !open(x-dx, y) || open(x+dx, y-dy) && !open(x, y-dy) {
+					return c, true
+				}
+				if s.jumps(x+dx, y, dx, 0) || s.jumps(x, y+dy, 0, dy) {
+					return c, true
+				}
+			} else if dx != 0 {
+				if open(x+dx, y+1) && !open(x, y+1) || open(x+dx, y-1) && !open(x, y-1) {
+					return c, true
+				}
+			} else {
+				if open(x+1, y+dy) && !open(x+1, y) || open(x-1, y+dy) && !open(x-1, y) {
+					return c, true
+				}
+			}
+		}
+		if !s.canMove(x+dx, y+dy, dx, dy) {
+			return intgeom.Point2{}, false
+		}
+		x += dx
+		y += dy
+	}
+}
+
+func (s *search) jumps(x, y, dx, dy int) bool {
+	_, ok := s.jump(x, y, dx, dy)
+	return ok
+}
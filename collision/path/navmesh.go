@@ -0,0 +1,406 @@
+package path
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/oakerr"
+)
+
+// A NavMesh is a set of triangles covering the area agents can move
+// through, for finding paths through worlds which do not fit well to grids.
+type NavMesh struct {
+	// Triangles are the triangles of the mesh, each with a z of 0. The
+	// points of each triangle are in counter-clockwise order, assuming y
+	// increases upward.
+	Triangles []floatgeom.Tri3
+	// neighbors[i][j] is the index of the triangle across the edge from
+	// Triangles[i][j] to Triangles[i][j+1], or -1 if there is none.
+	neighbors [][3]int
+}
+
+// NewNavMesh returns a NavMesh covering bounds, excluding the area within
+// each obstacle outline. Obstacles may overlap each other and the edges of
+// bounds. A render.Polygon's Points can be used as an obstacle.
+//
+// The area agentRadius away from each obstacle and from the edges of
+// bounds is also excluded, so that agents of that radius following paths
+// through the mesh will not overlap obstacles. Obstacles are grown by
+// moving their edges outward; the sharp corners of obstacles are cut off
+// where they would otherwise grow far past agentRadius.
+func NewNavMesh(bounds floatgeom.Rect2, agentRadius float64, obstacles ...[]floatgeom.Point2) (*NavMesh, error) {
+	if agentRadius < 0 || math.IsInf(agentRadius, 0) || math.IsNaN(agentRadius) {
+		return nil, oakerr.InvalidInput{InputName: "agentRadius"}
+	}
+	if bounds.W() <= 2*agentRadius || bounds.H() <= 2*agentRadius {
+		return nil, oakerr.InvalidInput{InputName: "bounds"}
+	}
+	inner := floatgeom.NewRect2(
+		bounds.Min.X()+agentRadius, bounds.Min.Y()+agentRadius,
+		bounds.Max.X()-agentRadius, bounds.Max.Y()-agentRadius,
+	)
+	outlines := make([][]floatgeom.Point2, len(obstacles))
+	extent := inner
+	for i, o := range obstacles {
+		if len(o) < 3 {
+			return nil, oakerr.InsufficientInputs{AtLeast: 3, InputName: "obstacles"}
+		}
+		outline, ok := inflate(o, agentRadius)
+		if !ok {
+			return nil, oakerr.InvalidInput{InputName: "obstacles"}
+		}
+		outlines[i] = outline
+		extent = extent.GreaterOf(floatgeom.NewBoundingRect2(outline...))
+	}
+
+	corners := rectOutline(inner)
+	segs := make([][2]floatgeom.Point2, 0, 4)
+	for _, o := range append(outlines, corners) {
+		for i, p := range o {
+			segs = append(segs, [2]floatgeom.Point2{p, o[(i+1)%len(o)]})
+		}
+	}
+	tr := newTriangulation(extent)
+	segs = splitSegments(segs, tr.eps)
+	constraints := make([][2]int, 0, len(segs))
+	for _, s := range segs {
+		a, b := tr.insert(s[0]), tr.insert(s[1])
+		if a != b {
+			constraints = append(constraints, [2]int{a, b})
+		}
+	}
+	if !tr.conform(constraints, 16*len(constraints)+1024) {
+		return nil, errors.New("obstacles could not be triangulated")
+	}
+
+	m := &NavMesh{}
+	index := make([]int, len(tr.tris))
+	for i, t := range tr.tris {
+		index[i] = -1
+		if t.dead || t.v[0] < 3 || t.v[1] < 3 || t.v[2] < 3 {
+			continue
+		}
+		a, b, c := tr.pts[t.v[0]], tr.pts[t.v[1]], tr.pts[t.v[2]]
+		if orient(a, b, c) <= 0 {
+			continue
+		}
+		// Every constraint is an edge of the triangulation, so each
+		// triangle is either entirely inside or outside of each outline
+		center := a.Add(b, c).DivConst(3)
+		if !strictlyContains(inner, center) || anyContains(outlines, center) {
+			continue
+		}
+		index[i] = len(m.Triangles)
+		m.Triangles = append(m.Triangles, floatgeom.Tri3{
+			{a.X(), a.Y(), 0},
+			{b.X(), b.Y(), 0},
+			{c.X(), c.Y(), 0},
+		})
+	}
+	m.neighbors = make([][3]int, len(m.Triangles))
+	for i, t := range tr.tris {
+		if index[i] == -1 {
+			continue
+		}
+		for j, n := range t.n {
+			if n != -1 {
+				n = index[n]
+			}
+			m.neighbors[index[i]][j] = n
+		}
+	}
+	return m, nil
+}
+
+// NewTreeNavMesh returns a NavMesh covering bounds, where the rectangles of
+// spaces in tree with one of the given labels are obstacles. If no labels
+// are given, all spaces are obstacles. If tree is nil, collision.DefTree is
+// used. See NewNavMesh.
+func NewTreeNavMesh(tree *collision.Tree, bounds floatgeom.Rect2, agentRadius float64, blocking ...collision.Label) (*NavMesh, error) {
+	if tree == nil {
+		tree = collision.DefTree
+	}
+	area := floatgeom.NewRect3(
+		bounds.Min.X(), bounds.Min.Y(), math.Inf(-1),
+		bounds.Max.X(), bounds.Max.Y(), math.Inf(1),
+	)
+	var obstacles [][]floatgeom.Point2
+	for _, sp := range tree.SearchIntersect(area) {
+		if sp == nil || !isBlocking(sp, blocking) {
+			continue
+		}
+		obstacles = append(obstacles, rectOutline(floatgeom.NewRect2(
+			sp.Location.Min.X(), sp.Location.Min.Y(),
+			sp.Location.Max.X(), sp.Location.Max.Y(),
+		)))
+	}
+	return NewNavMesh(bounds, agentRadius, obstacles...)
+}
+
+// isBlocking returns whether sp blocks paths, given the labels of
+// blocking spaces. If there are no labels, every space blocks paths.
+func isBlocking(sp *collision.Space, blocking []collision.Label) bool {
+	return len(blocking) == 0 || sp.HasLabel(blocking...)
+}
+
+func rectOutline(r floatgeom.Rect2) []floatgeom.Point2 {
+	return []floatgeom.Point2{
+		r.Min,
+		{r.Max.X(), r.Min.Y()},
+		r.Max,
+		{r.Min.X(), r.Max.Y()},
+	}
+}
+
+func strictlyContains(r floatgeom.Rect2, p floatgeom.Point2) bool {
+	return p.X() > r.Min.X() && p.X() < r.Max.X() &&
+		p.Y() > r.Min.Y() && p.Y() < r.Max.Y()
+}
+
+func anyContains(outlines [][]floatgeom.Point2, p floatgeom.Point2) bool {
+	for _, o := range outlines {
+		if outlineContains(o, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// outlineContains returns whether p is within the outline, by the even-odd
+// rule.
+func outlineContains(o []floatgeom.Point2, p floatgeom.Point2) bool {
+	in := false
+	for i, a := range o {
+		b := o[(i+1)%len(o)]
+		if (a.Y() > p.Y()) != (b.Y() > p.Y()) &&
+			p.X() < (b.X()-a.X())*(p.Y()-a.Y())/(b.Y()-a.Y())+a.X() {
+			in = !in
+		}
+	}
+	return in
+}
+
+// inflate returns the outline with each edge moved r outward, and false if
+// the outline has no area.
+func inflate(o []floatgeom.Point2, r float64) ([]floatgeom.Point2, bool) {
+	// Repeated points have no edge between them to move
+	deduped := make([]floatgeom.Point2, 0, len(o))
+	for i, p := range o {
+		if p != o[(i+1)%len(o)] {
+			deduped = append(deduped, p)
+		}
+	}
+	o = deduped
+	if len(o) < 3 {
+		return nil, false
+	}
+	area := 0.0
+	for i, p := range o {
+		area += orient(floatgeom.Point2{}, p, o[(i+1)%len(o)])
+	}
+	if area == 0 || math.IsNaN(area) {
+		return nil, false
+	}
+	if r == 0 {
+		return o, true
+	}
+	// Outward is to the right of each edge of counter-clockwise outlines
+	s := 1.0
+	if area < 0 {
+		s = -1
+	}
+	out := make([]floatgeom.Point2, 0, len(o))
+	for i, p := range o {
+		e1 := p.Sub(o[(i+len(o)-1)%len(o)]).Normalize()
+		e2 := o[(i+1)%len(o)].Sub(p).Normalize()
+		n1 := floatgeom.Point2{e1.Y(), -e1.X()}.MulConst(s)
+		n2 := floatgeom.Point2{e2.Y(), -e2.X()}.MulConst(s)
+		dot := n1.Dot(n2)
+		convex := orient(floatgeom.Point2{}, e1, e2)*s > 0
+		if convex && dot < -.5 || 1+dot < 1e-6 {
+			// The miter would be more than twice r from p, so cut it off
+			// with a line r from p
+			m := n1.Add(n2).Normalize()
+			if 1+dot < 1e-6 {
+				m = e1
+			}
+			t := r * (1 - n1.Dot(m)) / e1.Dot(m)
+			out = append(out,
+				p.Add(n1.MulConst(r), e1.MulConst(t)),
+				p.Add(n2.MulConst(r), e2.MulConst(-t)),
+			)
+			continue
+		}
+		out = append(out, p.Add(n1.Add(n2).MulConst(r/(1+dot))))
+	}
+	return out, true
+}
+
+// Contains returns whether p is within the mesh.
+func (m *NavMesh) Contains(p floatgeom.Point2) bool {
+	return m.locate(p) != -1
+}
+
+// locate returns the index of the triangle containing p, or -1.
+func (m *NavMesh) locate(p floatgeom.Point2) int {
+	for i, t := range m.Triangles {
+		b := t.Barycentric(p.X(), p.Y())
+		if b[0] >= -1e-9 && b[1] >= -1e-9 && b[2] >= -1e-9 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Path returns a shortest path from start to goal through the triangles
+// of the mesh, as the points at which the path begins, turns and ends. If
+// either point is outside of the mesh, or there is no path between them,
+// false is returned.
+//
+// Triangles are searched with A*, entering each triangle at the point on
+// its edge which is on the shortest way from where the previous triangle
+// was entered to goal, and the path through the triangles found is then
+// straightened with the funnel algorithm, as described in "Simple Stupid
+// Funnel Algorithm" by M. Mononen, 2010. The path returned is the shortest
+// through the triangles A* finds, which is usually but not always the
+// shortest through the whole mesh.
+func (m *NavMesh) Path(start, goal floatgeom.Point2) ([]floatgeom.Point2, bool) {
+	st, gt := m.locate(start), m.locate(goal)
+	if st == -1 || gt == -1 {
+		return nil, false
+	}
+	cost := make([]float64, len(m.Triangles))
+	parent := make([]int, len(m.Triangles))
+	closed := make([]bool, len(m.Triangles))
+	pos := make([]floatgeom.Point2, len(m.Triangles))
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		parent[i] = -1
+	}
+	cost[st] = 0
+	pos[st] = start
+	open := &nodeHeap{{i: st, f: start.Distance(goal)}}
+	found := false
+	for open.Len() > 0 {
+		i := heap.Pop(open).(node).i
+		if closed[i] {
+			continue
+		}
+		closed[i] = true
+		if i == gt {
+			found = true
+			break
+		}
+		for j, n := range m.neighbors[i] {
+			if n == -1 || closed[n] {
+				continue
+			}
+			a, b := m.edge(i, j)
+			p := portalPoint(pos[i], goal, a, b)
+			c := cost[i] + pos[i].Distance(p)
+			if c < cost[n] {
+				cost[n] = c
+				parent[n] = i
+				pos[n] = p
+				heap.Push(open, node{i: n, f: c + p.Distance(goal)})
+			}
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	var chain []int
+	for i := gt; i != -1; i = parent[i] {
+		chain = append(chain, i)
+	}
+	// Portals are the left and right ends of each edge crossed, going
+	// from start to goal
+	portals := make([][2]floatgeom.Point2, 0, len(chain)+1)
+	portals = append(portals, [2]floatgeom.Point2{start, start})
+	for k := len(chain) - 1; k > 0; k-- {
+		for j, n := range m.neighbors[chain[k]] {
+			if n == chain[k-1] {
+				right, left := m.edge(chain[k], j)
+				portals = append(portals, [2]floatgeom.Point2{left, right})
+				break
+			}
+		}
+	}
+	portals = append(portals, [2]floatgeom.Point2{goal, goal})
+	return funnel(portals), true
+}
+
+// portalPoint returns the point on the segment from a to b which minimizes
+// the distance from 'from' to the point plus the distance from the point to
+// goal.
+func portalPoint(from, goal, a, b floatgeom.Point2) floatgeom.Point2 {
+	d := b.Sub(a)
+	sf, sg := orient(a, b, from), orient(a, b, goal)
+	if sf*sg > 0 {
+		// Reflect goal across the line through a and b, so the line from
+		// 'from' to goal crosses it at the best point
+		proj := a.Add(d.MulConst(goal.Sub(a).Dot(d) / d.Dot(d)))
+		goal = proj.MulConst(2).Sub(goal)
+		sg = -sg
+	}
+	if sf == sg {
+		return a.Add(b).MulConst(.5)
+	}
+	x := from.Add(goal.Sub(from).MulConst(sf / (sf - sg)))
+	u := math.Max(0, math.Min(1, x.Sub(a).Dot(d)/d.Dot(d)))
+	return a.Add(d.MulConst(u))
+}
+
+// edge returns the points of the jth edge of the ith triangle.
+func (m *NavMesh) edge(i, j int) (floatgeom.Point2, floatgeom.Point2) {
+	t := m.Triangles[i]
+	a, b := t[j], t[(j+1)%3]
+	return floatgeom.Point2{a.X(), a.Y()}, floatgeom.Point2{b.X(), b.Y()}
+}
+
+// funnel returns the shortest path through a series of portals, where the
+// first and last portals are the start and end of the path.
+func funnel(portals [][2]floatgeom.Point2) []floatgeom.Point2 {
+	apex := portals[0][0]
+	left, right := apex, apex
+	apexI, leftI, rightI := 0, 0, 0
+	path := []floatgeom.Point2{apex}
+	for i := 1; i < len(portals); i++ {
+		l, r := portals[i][0], portals[i][1]
+		// Narrow the funnel from the right
+		if orient(apex, right, r) >= 0 {
+			if apex == right || orient(apex, left, r) < 0 {
+				right, rightI = r, i
+			} else {
+				// The right side crossed over the left, so the path turns
+				// at the left side
+				path = append(path, left)
+				apex, apexI = left, leftI
+				left, right = apex, apex
+				leftI, rightI = apexI, apexI
+				i = apexI
+				continue
+			}
+		}
+		// Narrow the funnel from the left
+		if orient(apex, left, l) <= 0 {
+			if apex == left || orient(apex, right, l) > 0 {
+				left, leftI = l, i
+			} else {
+				path = append(path, right)
+				apex, apexI = right, rightI
+				left, right = apex, apex
+				leftI, rightI = apexI, apexI
+				i = apexI
+				continue
+			}
+		}
+	}
+	if goal := portals[len(portals)-1][0]; path[len(path)-1] != goal {
+		path = append(path, goal)
+	}
+	return path
+}
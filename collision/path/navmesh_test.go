@@ -0,0 +1,164 @@
+package path
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/render"
+	"github.com/stretchr/testify/assert"
+)
+
+func meshArea(m *NavMesh) float64 {
+	area := 0.0
+	for _, t := range m.Triangles {
+		a := floatgeom.Point2{t[0].X(), t[0].Y()}
+		b := floatgeom.Point2{t[1].X(), t[1].Y()}
+		c := floatgeom.Point2{t[2].X(), t[2].Y()}
+		area += orient(a, b, c) / 2
+	}
+	return area
+}
+
+func TestNewNavMesh(t *testing.T) {
+	bounds := floatgeom.NewRect2(0, 0, 100, 100)
+	_, err := NewNavMesh(bounds, -1)
+	assert.NotNil(t, err)
+	_, err = NewNavMesh(bounds, 50)
+	assert.NotNil(t, err)
+	_, err = NewNavMesh(bounds, 0, []floatgeom.Point2{{1, 1}, {2, 2}})
+	assert.NotNil(t, err)
+	_, err = NewNavMesh(bounds, 0, []floatgeom.Point2{{1, 1}, {2, 2}, {3, 3}})
+	assert.NotNil(t, err)
+
+	m, err := NewNavMesh(bounds, 0)
+	assert.Nil(t, err)
+	assert.InDelta(t, 10000, meshArea(m), 1e-6)
+
+	// Overlapping obstacles, and obstacles past the edge of the bounds
+	m, err = NewNavMesh(bounds, 0,
+		rectOutline(floatgeom.NewRect2(10, 10, 30, 30)),
+		rectOutline(floatgeom.NewRect2(20, 20, 40, 40)),
+		rectOutline(floatgeom.NewRect2(90, 90, 120, 120)),
+	)
+	assert.Nil(t, err)
+	assert.InDelta(t, 10000-700-100, meshArea(m), 1e-6)
+	assert.True(t, m.Contains(floatgeom.Point2{15, 35}))
+	assert.False(t, m.Contains(floatgeom.Point2{25, 25}))
+	assert.False(t, m.Contains(floatgeom.Point2{95, 95}))
+	assert.False(t, m.Contains(floatgeom.Point2{-5, 50}))
+
+	// Radius inflation
+	m, err = NewNavMesh(bounds, 5, rectOutline(floatgeom.NewRect2(40, 40, 60, 60)))
+	assert.Nil(t, err)
+	assert.InDelta(t, 90*90-30*30, meshArea(m), 1e-6)
+	assert.False(t, m.Contains(floatgeom.Point2{37, 50}))
+	assert.False(t, m.Contains(floatgeom.Point2{3, 50}))
+	assert.True(t, m.Contains(floatgeom.Point2{33, 50}))
+}
+
+func TestNewTreeNavMesh(t *testing.T) {
+	tree, _ := collision.NewTree()
+	tree.Add(collision.NewLabeledSpace(40, 40, 20, 20, wall))
+	tree.Add(collision.NewLabeledSpace(10, 10, 10, 10, floor))
+	m, err := NewTreeNavMesh(tree, floatgeom.NewRect2(0, 0, 100, 100), 0, wall)
+	assert.Nil(t, err)
+	assert.InDelta(t, 10000-400, meshArea(m), 1e-6)
+	m, err = NewTreeNavMesh(tree, floatgeom.NewRect2(0, 0, 100, 100), 0)
+	assert.Nil(t, err)
+	assert.InDelta(t, 10000-500, meshArea(m), 1e-6)
+}
+
+func TestNavMeshPath(t *testing.T) {
+	bounds := floatgeom.NewRect2(0, 0, 100, 100)
+	m, err := NewNavMesh(bounds, 0, rectOutline(floatgeom.NewRect2(40, 20, 60, 80)))
+	assert.Nil(t, err)
+
+	path, ok := m.Path(floatgeom.Point2{10, 50}, floatgeom.Point2{90, 50})
+	assert.True(t, ok)
+	assert.Len(t, path, 4)
+	assert.InDelta(t, 2*math.Hypot(30, 30)+20, pathLength(path), 1e-6)
+
+	path, ok = m.Path(floatgeom.Point2{10, 10}, floatgeom.Point2{90, 10})
+	assert.True(t, ok)
+	assert.Equal(t, []floatgeom.Point2{{10, 10}, {90, 10}}, path)
+
+	path, ok = m.Path(floatgeom.Point2{10, 10}, floatgeom.Point2{10, 10})
+	assert.True(t, ok)
+	assert.Equal(t, []floatgeom.Point2{{10, 10}}, path)
+
+	_, ok = m.Path(floatgeom.Point2{10, 10}, floatgeom.Point2{50, 50})
+	assert.False(t, ok)
+
+	// A triangular render.Polygon obstacle, with an agent radius
+	pg, _ := render.NewPolygon(
+		floatgeom.Point2{50, 10},
+		floatgeom.Point2{80, 90},
+		floatgeom.Point2{20, 90},
+	)
+	m, err = NewNavMesh(bounds, 2, pg.Points())
+	assert.Nil(t, err)
+	path, ok = m.Path(floatgeom.Point2{10, 50}, floatgeom.Point2{90, 50})
+	assert.True(t, ok)
+	// The sharp top corner is cut off, leaving two corners to turn at
+	assert.Len(t, path, 4)
+	assert.True(t, path[1].Y() < 10)
+	assert.True(t, path[2].Y() < 10)
+
+	// A wall with no gap
+	m, err = NewNavMesh(bounds, 0, rectOutline(floatgeom.NewRect2(40, -10, 60, 110)))
+	assert.Nil(t, err)
+	_, ok = m.Path(floatgeom.Point2{10, 50}, floatgeom.Point2{90, 50})
+	assert.False(t, ok)
+}
+
+func TestNavMeshRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	bounds := floatgeom.NewRect2(0, 0, 200, 200)
+	for trial := 0; trial < 10; trial++ {
+		var obstacles [][]floatgeom.Point2
+		var rects []floatgeom.Rect2
+		for i := 0; i < 15; i++ {
+			x, y := rng.Float64()*200, rng.Float64()*200
+			r := floatgeom.NewRect2WH(x, y, 5+rng.Float64()*30, 5+rng.Float64()*30)
+			rects = append(rects, floatgeom.NewRect2(r.Min.X()-3, r.Min.Y()-3, r.Max.X()+3, r.Max.Y()+3))
+			obstacles = append(obstacles, rectOutline(r))
+		}
+		m, err := NewNavMesh(bounds, 3, obstacles...)
+		assert.Nil(t, err)
+		free := func(p floatgeom.Point2) bool {
+			if p.X() < 3-1e-6 || p.Y() < 3-1e-6 || p.X() > 197+1e-6 || p.Y() > 197+1e-6 {
+				return false
+			}
+			for _, r := range rects {
+				if p.X() > r.Min.X()+1e-6 && p.X() < r.Max.X()-1e-6 &&
+					p.Y() > r.Min.Y()+1e-6 && p.Y() < r.Max.Y()-1e-6 {
+					return false
+				}
+			}
+			return true
+		}
+		for i := 0; i < 200; i++ {
+			p := floatgeom.Point2{rng.Float64() * 200, rng.Float64() * 200}
+			assert.Equal(t, free(p), m.Contains(p), p)
+		}
+		for i := 0; i < 20; i++ {
+			start := floatgeom.Point2{rng.Float64() * 200, rng.Float64() * 200}
+			goal := floatgeom.Point2{rng.Float64() * 200, rng.Float64() * 200}
+			path, ok := m.Path(start, goal)
+			if !ok {
+				continue
+			}
+			assert.Equal(t, start, path[0])
+			assert.Equal(t, goal, path[len(path)-1])
+			for k := 1; k < len(path); k++ {
+				for f := 0.0; f <= 1; f += .01 {
+					p := path[k-1].Add(path[k].Sub(path[k-1]).MulConst(f))
+					assert.True(t, free(p), p)
+				}
+			}
+		}
+	}
+}
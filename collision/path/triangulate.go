@@ -0,0 +1,345 @@
+package path
+
+import (
+	"math"
+	"sort"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+)
+
+// A triangulation is a Delaunay triangulation built by inserting points
+// one at a time, per "Computing the n-dimensional Delaunay tessellation
+// with application to Voronoi polytopes" by A. Bowyer, and "Computing
+// Dirichlet tessellations" by D. Watson, The Computer Journal, 1981.
+//
+// Its first three points are the corners of a triangle which contains all
+// other points.
+type triangulation struct {
+	pts  []floatgeom.Point2
+	tris []meshTri
+	// eps is the distance within which points are considered equal
+	eps float64
+	// buckets stores the indices of points by their position rounded to
+	// a multiple of eps, for finding equal points
+	buckets map[[2]int64][]int
+	// last is the most recently created triangle, where searches for the
+	// triangle containing a point begin
+	last int
+}
+
+// A meshTri is a counter-clockwise triangle in a triangulation, where n[i]
+// is the index of the triangle across the edge from v[i] to v[i+1], or -1.
+type meshTri struct {
+	v    [3]int
+	n    [3]int
+	dead bool
+}
+
+func newTriangulation(bounds floatgeom.Rect2) *triangulation {
+	c := floatgeom.Point2{bounds.Midpoint(0), bounds.Midpoint(1)}
+	d := math.Max(math.Max(bounds.W(), bounds.H()), 1)
+	return &triangulation{
+		pts: []floatgeom.Point2{
+			{c.X() - 20*d, c.Y() - d},
+			{c.X() + 20*d, c.Y() - d},
+			{c.X(), c.Y() + 20*d},
+		},
+		tris:    []meshTri{{v: [3]int{0, 1, 2}, n: [3]int{-1, -1, -1}}},
+		eps:     d * 1e-9,
+		buckets: make(map[[2]int64][]int),
+	}
+}
+
+func (tr *triangulation) bucket(p floatgeom.Point2) [2]int64 {
+	return [2]int64{int64(math.Floor(p.X() / tr.eps)), int64(math.Floor(p.Y() / tr.eps))}
+}
+
+// find returns the index of a point within eps of p, or -1.
+func (tr *triangulation) find(p floatgeom.Point2) int {
+	b := tr.bucket(p)
+	for x := b[0] - 1; x <= b[0]+1; x++ {
+		for y := b[1] - 1; y <= b[1]+1; y++ {
+			for _, i := range tr.buckets[[2]int64{x, y}] {
+				if tr.pts[i].Distance(p) <= tr.eps {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+func (tr *triangulation) contains(t meshTri, p floatgeom.Point2) bool {
+	a, b, c := tr.pts[t.v[0]], tr.pts[t.v[1]], tr.pts[t.v[2]]
+	return side(a, b, p) >= -tr.eps && side(b, c, p) >= -tr.eps && side(c, a, p) >= -tr.eps
+}
+
+// locate returns the index of a triangle containing p, or -1. Triangles are
+// walked through toward p from the last triangle created.
+func (tr *triangulation) locate(p floatgeom.Point2) int {
+	i := tr.last
+	for steps := 0; steps < len(tr.tris); steps++ {
+		t := tr.tris[i]
+		next := -1
+		for j := range t.v {
+			if side(tr.pts[t.v[j]], tr.pts[t.v[(j+1)%3]], p) < -tr.eps {
+				next = t.n[j]
+				break
+			}
+		}
+		if next == -1 {
+			break
+		}
+		i = next
+	}
+	if tr.contains(tr.tris[i], p) {
+		return i
+	}
+	// Walks can fail near rounding errors, so fall back to checking every
+	// triangle
+	for i, t := range tr.tris {
+		if !t.dead && tr.contains(t, p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// orient returns twice the signed area of the triangle abc, which is
+// positive when abc is counter-clockwise.
+func orient(a, b, c floatgeom.Point2) float64 {
+	return (b.X()-a.X())*(c.Y()-a.Y()) - (b.Y()-a.Y())*(c.X()-a.X())
+}
+
+// side returns the signed distance of p from the line through a and b,
+// which is positive to the left of a to b.
+func side(a, b, p floatgeom.Point2) float64 {
+	l := a.Distance(b)
+	if l == 0 {
+		return 0
+	}
+	return orient(a, b, p) / l
+}
+
+// inCircle returns whether p is within the circumcircle of the
+// counter-clockwise triangle abc.
+func inCircle(a, b, c, p floatgeom.Point2) bool {
+	adx, ady := a.X()-p.X(), a.Y()-p.Y()
+	bdx, bdy := b.X()-p.X(), b.Y()-p.Y()
+	cdx, cdy := c.X()-p.X(), c.Y()-p.Y()
+	al := adx*adx + ady*ady
+	bl := bdx*bdx + bdy*bdy
+	cl := cdx*cdx + cdy*cdy
+	return adx*(bdy*cl-bl*cdy)-ady*(bdx*cl-bl*cdx)+al*(bdx*cdy-bdy*cdx) > 0
+}
+
+// insert adds p to the triangulation and returns its index. If the
+// triangulation already has a point within eps of p, that point's index is
+// returned instead.
+func (tr *triangulation) insert(p floatgeom.Point2) int {
+	if i := tr.find(p); i != -1 {
+		return i
+	}
+	start := tr.locate(p)
+	if start == -1 {
+		return -1
+	}
+
+	// The cavity is every triangle connected to the one containing p
+	// whose circumcircle contains p
+	cavity := map[int]bool{start: true}
+	cavityTris := []int{start}
+	for k := 0; k < len(cavityTris); k++ {
+		for _, n := range tr.tris[cavityTris[k]].n {
+			if n == -1 || cavity[n] {
+				continue
+			}
+			nt := tr.tris[n]
+			if inCircle(tr.pts[nt.v[0]], tr.pts[nt.v[1]], tr.pts[nt.v[2]], p) {
+				cavity[n] = true
+				cavityTris = append(cavityTris, n)
+			}
+		}
+	}
+
+	// Rounding can leave the cavity with edges p cannot see, which would
+	// make inverted triangles, so grow the cavity past each of them
+	type edge struct {
+		a, b, outer int
+	}
+	var boundary []edge
+	for grown := true; grown; {
+		grown = false
+		boundary = boundary[:0]
+		for _, i := range cavityTris {
+			t := tr.tris[i]
+			for j, n := range t.n {
+				if n != -1 && cavity[n] {
+					continue
+				}
+				a, b := t.v[j], t.v[(j+1)%3]
+				if n != -1 && side(tr.pts[a], tr.pts[b], p) <= tr.eps {
+					cavity[n] = true
+					cavityTris = append(cavityTris, n)
+					grown = true
+					break
+				}
+				boundary = append(boundary, edge{a, b, n})
+			}
+			if grown {
+				break
+			}
+		}
+	}
+
+	pi := len(tr.pts)
+	tr.pts = append(tr.pts, p)
+	b := tr.bucket(p)
+	tr.buckets[b] = append(tr.buckets[b], pi)
+	for _, i := range cavityTris {
+		tr.tris[i].dead = true
+	}
+	startAt := make(map[int]int, len(boundary))
+	endAt := make(map[int]int, len(boundary))
+	for _, e := range boundary {
+		k := len(tr.tris)
+		tr.tris = append(tr.tris, meshTri{
+			v: [3]int{e.a, e.b, pi},
+			n: [3]int{e.outer, -1, -1},
+		})
+		if e.outer != -1 {
+			ot := &tr.tris[e.outer]
+			for j := range ot.v {
+				if ot.v[j] == e.b && ot.v[(j+1)%3] == e.a {
+					ot.n[j] = k
+				}
+			}
+		}
+		startAt[e.a] = k
+		endAt[e.b] = k
+	}
+	for _, e := range boundary {
+		k := startAt[e.a]
+		tr.tris[k].n[1] = startAt[e.b]
+		tr.tris[k].n[2] = endAt[e.a]
+	}
+	tr.last = len(tr.tris) - 1
+	return pi
+}
+
+// edges returns the set of edges in the triangulation.
+func (tr *triangulation) edges() map[[2]int]bool {
+	edges := make(map[[2]int]bool)
+	for _, t := range tr.tris {
+		if t.dead {
+			continue
+		}
+		for j := range t.v {
+			edges[edgeKey(t.v[j], t.v[(j+1)%3])] = true
+		}
+	}
+	return edges
+}
+
+func edgeKey(a, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
+
+// conform inserts points along each constraint which is not an edge of the
+// triangulation until all of them are, returning false if more than limit
+// points would need to be inserted. Inserting a point can remove edges
+// which were already constraints, so every constraint is checked again
+// after each round of insertions.
+func (tr *triangulation) conform(constraints [][2]int, limit int) bool {
+	for {
+		edges := tr.edges()
+		var next [][2]int
+		inserted := false
+		for _, c := range constraints {
+			if edges[edgeKey(c[0], c[1])] {
+				next = append(next, c)
+				continue
+			}
+			if limit == 0 {
+				return false
+			}
+			limit--
+			a, b := tr.pts[c[0]], tr.pts[c[1]]
+			m := tr.insert(a.Add(b).MulConst(.5))
+			if m == -1 || m == c[0] || m == c[1] {
+				return false
+			}
+			inserted = true
+			next = append(next, [2]int{c[0], m}, [2]int{m, c[1]})
+		}
+		if !inserted {
+			return true
+		}
+		constraints = next
+	}
+}
+
+// splitSegments splits each segment at every point where it touches
+// another segment, so that no two segments cross and no segment passes
+// through the end of another.
+func splitSegments(segs [][2]floatgeom.Point2, eps float64) [][2]floatgeom.Point2 {
+	var out [][2]floatgeom.Point2
+	for i, s := range segs {
+		d := s[1].Sub(s[0])
+		l := d.Magnitude()
+		if l <= eps {
+			continue
+		}
+		ts := []float64{0, 1}
+		addPoint := func(q floatgeom.Point2) {
+			if math.Abs(side(s[0], s[1], q)) > eps {
+				return
+			}
+			t := q.Sub(s[0]).Dot(d) / (l * l)
+			if t*l > eps && (1-t)*l > eps {
+				ts = append(ts, t)
+			}
+		}
+		for j, s2 := range segs {
+			if i == j {
+				continue
+			}
+			addPoint(s2[0])
+			addPoint(s2[1])
+			if p, ok := segmentIntersection(s, s2); ok {
+				addPoint(p)
+			}
+		}
+		sort.Float64s(ts)
+		for k := 1; k < len(ts); k++ {
+			if (ts[k]-ts[k-1])*l <= eps {
+				continue
+			}
+			out = append(out, [2]floatgeom.Point2{
+				s[0].Add(d.MulConst(ts[k-1])),
+				s[0].Add(d.MulConst(ts[k])),
+			})
+		}
+	}
+	return out
+}
+
+// segmentIntersection returns where two segments cross, if they do.
+func segmentIntersection(s1, s2 [2]floatgeom.Point2) (floatgeom.Point2, bool) {
+	d1 := s1[1].Sub(s1[0])
+	d2 := s2[1].Sub(s2[0])
+	denom := d1.X()*d2.Y() - d1.Y()*d2.X()
+	if denom == 0 {
+		return floatgeom.Point2{}, false
+	}
+	diff := s2[0].Sub(s1[0])
+	t := (diff.X()*d2.Y() - diff.Y()*d2.X()) / denom
+	u := (diff.X()*d1.Y() - diff.Y()*d1.X()) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return floatgeom.Point2{}, false
+	}
+	return s1[0].Add(d1.MulConst(t)), true
+}
@@ -8,11 +8,21 @@ import "github.com/oakmound/oak/alg/floatgeom"
 type Point struct {
 	floatgeom.Point3
 	Zone *Space
+	// Points returned from ray casts also record how far along the ray
+	// the point was, the normal of the side of Zone the ray entered
+	// through, and where and how far along the ray it exited Zone.
+	Distance     float64
+	Normal       floatgeom.Point2
+	Exit         floatgeom.Point3
+	ExitDistance float64
 }
 
 // NewPoint creates a new point
 func NewPoint(s *Space, x, y float64) Point {
-	return Point{floatgeom.Point3{x, y, 0}, s}
+	return Point{
+		Point3: floatgeom.Point3{x, y, 0},
+		Zone:   s,
+	}
 }
 
 // IsNil returns whether the underlying zone of a Point is nil
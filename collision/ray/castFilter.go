@@ -23,12 +23,7 @@ func AddFilter(cf CastFilter) CastOption {
 // will dominate.
 func AcceptLabels(ls ...collision.Label) CastOption {
 	return AddFilter(func(s *collision.Space) bool {
-		for _, l := range ls {
-			if s.Label == l {
-				return true
-			}
-		}
-		return false
+		return s.HasLabel(ls...)
 	})
 }
 
@@ -36,12 +31,7 @@ func AcceptLabels(ls ...collision.Label) CastOption {
 // spaces with labels in the set of input labels.
 func IgnoreLabels(ls ...collision.Label) CastOption {
 	return AddFilter(func(s *collision.Space) bool {
-		for _, l := range ls {
-			if s.Label == l {
-				return false
-			}
-		}
-		return true
+		return !s.HasLabel(ls...)
 	})
 }
 
@@ -36,15 +36,7 @@ func LimitResults(limit int) CastOption {
 func StopAtLabel(ls ...collision.Label) CastOption {
 	return AddLimit(func(ps []collision.Point) bool {
 		z := ps[len(ps)-1].Zone
-		if z == nil {
-			return true
-		}
-		for _, l := range ls {
-			if z.Label == l {
-				return false
-			}
-		}
-		return true
+		return z == nil || !z.HasLabel(ls...)
 	})
 }
 
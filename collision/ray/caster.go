@@ -1,8 +1,6 @@
 package ray
 
 import (
-	"math"
-
 	"github.com/oakmound/oak/alg/floatgeom"
 	"github.com/oakmound/oak/collision"
 )
@@ -18,6 +16,7 @@ var (
 		// Consider: Cast() could take in distance as well.
 		CastDistance: 200,
 		Tree:         collision.DefTree,
+		Label:        collision.NilLabel,
 	}
 )
 
@@ -34,10 +33,18 @@ func SetDefaultCaster(caster *Caster) {
 type Caster struct {
 	Filters      []CastFilter
 	Limits       []CastLimit
-	PointSize    floatgeom.Point2
-	PointSpan    float64
 	CastDistance float64
 	Tree         *collision.Tree
+	// Label is the label rays from this caster have when checked against
+	// the collision matrix of Tree. Spaces whose labels cannot collide with
+	// Label will not be hit.
+	Label collision.Label
+	// Deprecated: Casts are exact and no longer sample points along rays,
+	// so PointSize has no effect.
+	PointSize floatgeom.Point2
+	// Deprecated: PointSpan has no effect, see PointSize.
+	PointSpan float64
+	// Deprecated: CenterPoints has no effect, see PointSize.
 	CenterPoints bool
 }
 
@@ -63,48 +70,31 @@ func (c *Caster) CastTo(origin, target floatgeom.Point2) []collision.Point {
 }
 
 // Cast creates a ray from origin pointing at the given angle and returns
-// some spaces collided with at the point of collision, given the settings of
-// this Caster. By default, all spaces hit will be returned.
+// the points where the ray enters each space it hits, in order of distance
+// from origin, given the settings of this Caster. By default, all spaces hit
+// will be returned. Spaces are hit as their rectangles.
 func (c *Caster) Cast(origin, angle floatgeom.Point2) []collision.Point {
 	points := make([]collision.Point, 0)
-	resultHash := make(map[*collision.Space]bool)
-
-	x := origin.X()
-	y := origin.Y()
-
-	degrees := angle.ToRadians()
-	sin := math.Sin(degrees)
-	cos := math.Cos(degrees)
-
-	for i := 0.0; i < c.CastDistance; i += c.PointSpan {
+	matrix := c.Tree.Matrix()
 
-		hits := c.Tree.SearchIntersect(
-			collision.NewRect(x, y, c.PointSize.X(), c.PointSize.Y()),
-		)
-
-	hitLoop:
-		for k := 0; k < len(hits); k++ {
-			next := hits[k]
-			if _, ok := resultHash[next]; !ok {
-				resultHash[next] = true
-
-				for _, f := range c.Filters {
-					if !f(next) {
-						continue hitLoop
-					}
-				}
+hitLoop:
+	for _, next := range c.Tree.RayHits(origin, angle, c.CastDistance) {
+		if !matrix.Collides(c.Label, next.Zone.Label) {
+			continue
+		}
+		for _, f := range c.Filters {
+			if !f(next.Zone) {
+				continue hitLoop
+			}
+		}
 
-				points = append(points, collision.NewPoint(next, x, y))
+		points = append(points, next)
 
-				for _, l := range c.Limits {
-					if !l(points) {
-						return points
-					}
-				}
+		for _, l := range c.Limits {
+			if !l(points) {
+				return points
 			}
 		}
-		x += cos
-		y += sin
 	}
 	return points
 }
@@ -133,9 +123,19 @@ func Tree(t *collision.Tree) CastOption {
 	}
 }
 
+// Label sets the label a Caster's rays have when checked against the
+// collision matrix of its Tree.
+func Label(l collision.Label) CastOption {
+	return func(c *Caster) {
+		c.Label = l
+	}
+}
+
 // CenterPoints sets whether a Caster should center its collision points that
 // form its ray. This is by default false, and is only significant if said
 // points' dimensions are significantly large.
+//
+// Deprecated: Casts are exact, so this has no effect.
 func CenterPoints(on bool) CastOption {
 	return func(c *Caster) {
 		c.CenterPoints = on
@@ -150,6 +150,8 @@ func Distance(dist float64) CastOption {
 }
 
 // PointSize determines the size of a caster's collision checks
+//
+// Deprecated: Casts are exact, so this has no effect.
 func PointSize(ps floatgeom.Point2) CastOption {
 	return func(c *Caster) {
 		c.PointSize = ps
@@ -157,6 +159,8 @@ func PointSize(ps floatgeom.Point2) CastOption {
 }
 
 // PointSpan determines the distance between collision check points
+//
+// Deprecated: Casts are exact, so this has no effect.
 func PointSpan(span float64) CastOption {
 	return func(c *Caster) {
 		c.PointSpan = span
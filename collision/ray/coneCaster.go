@@ -48,7 +48,7 @@ func NewConeCaster(opts ...ConeCastOption) *ConeCaster {
 
 // CastTo casts a ray from origin to target, and otherwise acts as Cast.
 func (cc *ConeCaster) CastTo(origin, target floatgeom.Point2) []collision.Point {
-	return cc.Cast(origin, floatgeom.AnglePoint(origin.AngleTo(target)))
+	return cc.Cast(origin, floatgeom.AnglePoint(target.AngleTo(origin)))
 }
 
 // Cast creates a ray from origin pointing at the given angle and returns
@@ -1,3 +1,3 @@
-// Package ray holds utilities for performing iterative collision checks
-// or raycasts
+// Package ray holds utilities for performing raycasts against
+// collision trees
 package ray
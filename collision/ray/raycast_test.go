@@ -22,3 +22,57 @@ func TestRaycasts(t *testing.T) {
 		assert.Empty(t, ConeCastTo(p1, p2))
 	}
 }
+
+func TestCastMatrix(t *testing.T) {
+	tree, _ := collision.NewTree()
+	wall := collision.NewLabeledSpace(10, -5, 5, 10, 1)
+	tree.Add(wall)
+	c := NewCaster(Tree(tree), Distance(50), Label(2))
+	assert.Len(t, c.Cast(floatgeom.Point2{0, 0}, floatgeom.Point2{1, 0}), 1)
+
+	m := collision.NewMatrix(true)
+	m.Set(1, 2, false)
+	tree.SetMatrix(m)
+	assert.Empty(t, c.Cast(floatgeom.Point2{0, 0}, floatgeom.Point2{1, 0}))
+	c2 := NewCaster(Tree(tree), Distance(50))
+	assert.Len(t, c2.Cast(floatgeom.Point2{0, 0}, floatgeom.Point2{1, 0}), 1)
+}
+
+func TestCastOrder(t *testing.T) {
+	tree, _ := collision.NewTree()
+	s1 := collision.NewFullSpace(10, -5, 5, 10, 1, 1)
+	s2 := collision.NewFullSpace(20, -5, .01, 10, 2, 2)
+	s3 := collision.NewFullSpace(30, -5, 5, 10, 1, 3)
+	tree.Add(s3, s1, s2)
+	origin := floatgeom.Point2{0, 0}
+	target := floatgeom.Point2{100, 0}
+
+	pts := NewCaster(Tree(tree)).CastTo(origin, target)
+	assert.Len(t, pts, 3)
+	assert.Equal(t, s1, pts[0].Zone)
+	assert.Equal(t, 10.0, pts[0].X())
+	assert.Equal(t, s2, pts[1].Zone)
+	assert.Equal(t, s3, pts[2].Zone)
+
+	pts = NewCaster(Tree(tree), Pierce(1)).CastTo(origin, target)
+	assert.Len(t, pts, 2)
+	assert.Equal(t, s2, pts[0].Zone)
+
+	pts = NewCaster(Tree(tree), IgnoreLabels(2), LimitResults(1)).CastTo(origin, target)
+	assert.Len(t, pts, 1)
+	assert.Equal(t, s1, pts[0].Zone)
+
+	pts = NewCaster(Tree(tree), StopAtID(2)).CastTo(origin, target)
+	assert.Len(t, pts, 2)
+
+	pts = NewCaster(Tree(tree), Distance(25)).CastTo(origin, target)
+	assert.Len(t, pts, 2)
+
+	cc := NewConeCaster(ConeRays(2), ConeSpread(10))
+	cc.Caster = NewCaster(Tree(tree), AcceptLabels(1))
+	pts = cc.CastTo(origin, target)
+	assert.Len(t, pts, 6)
+	for _, p := range pts {
+		assert.Equal(t, collision.Label(1), p.Zone.Label)
+	}
+}
@@ -0,0 +1,143 @@
+package collision
+
+import (
+	"math"
+	"sort"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+)
+
+// A RaySearcher is a Backend which can find the spaces a ray passes through
+// faster than by searching the ray's bounding rectangle.
+type RaySearcher interface {
+	// SearchRay returns all spaces whose rectangles the ray from origin
+	// in the unit direction dir passes through within dist of origin.
+	SearchRay(origin, dir floatgeom.Point2, dist float64) []*Space
+}
+
+// RayHits returns every space in the tree that a ray from origin in the
+// direction dir passes through within dist of origin, as Points ordered by
+// the distance at which the ray enters each space. Each Point is where the
+// ray enters its space, or origin if origin is within the space. Spaces are
+// hit as their rectangles, ignoring any shapes they have.
+func (t *Tree) RayHits(origin, dir floatgeom.Point2, dist float64) []Point {
+	dir = dir.Normalize()
+	if dir.X() == 0 && dir.Y() == 0 || dist <= 0 {
+		return []Point{}
+	}
+	t.RLock()
+	defer t.RUnlock()
+	var candidates []*Space
+	if rs, ok := t.Backend.(RaySearcher); ok {
+		candidates = rs.SearchRay(origin, dir, dist)
+	} else {
+		candidates = t.Backend.SearchIntersect(rayBounds(origin, dir, dist))
+	}
+	points := make([]Point, 0, len(candidates))
+	for _, sp := range candidates {
+		if sp == nil {
+			continue
+		}
+		entry, exit, normal, ok := rayRect(origin, dir, dist, sp.Location)
+		if !ok {
+			continue
+		}
+		p := origin.Add(dir.MulConst(entry))
+		e := origin.Add(dir.MulConst(exit))
+		points = append(points, Point{
+			Point3:       floatgeom.Point3{p.X(), p.Y(), 0},
+			Zone:         sp,
+			Distance:     entry,
+			Normal:       normal,
+			Exit:         floatgeom.Point3{e.X(), e.Y(), 0},
+			ExitDistance: exit,
+		})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Distance < points[j].Distance
+	})
+	return points
+}
+
+// rayBounds returns the bounding rectangle of a ray, spanning every z. Each
+// axis is bounded separately, so that infinite rays which do not move along
+// an axis are bounded by their origin on that axis, rather than by NaN.
+func rayBounds(origin, dir floatgeom.Point2, dist float64) floatgeom.Rect3 {
+	bounds := floatgeom.Rect3{
+		Min: floatgeom.Point3{0, 0, math.Inf(-1)},
+		Max: floatgeom.Point3{0, 0, math.Inf(1)},
+	}
+	for i := 0; i < 2; i++ {
+		bounds.Min[i], bounds.Max[i] = origin[i], origin[i]
+		if dir[i] == 0 {
+			continue
+		}
+		end := origin[i] + dir[i]*dist
+		bounds.Min[i] = math.Min(bounds.Min[i], end)
+		bounds.Max[i] = math.Max(bounds.Max[i], end)
+	}
+	return bounds
+}
+
+// rayRect returns the distances along a ray at which it enters and exits
+// a rectangle, clamped to [0, dist], and the normal of the side it enters
+// through. If the ray starts within the rectangle, the normal is zero. Only
+// the x and y dimensions are considered.
+func rayRect(origin, dir floatgeom.Point2, dist float64, r floatgeom.Rect3) (float64, float64, floatgeom.Point2, bool) {
+	tMin := math.Inf(-1)
+	tMax := math.Inf(1)
+	axis := -1
+	for i := 0; i < 2; i++ {
+		if dir[i] == 0 {
+			if origin[i] < r.Min[i] || origin[i] > r.Max[i] {
+				return 0, 0, floatgeom.Point2{}, false
+			}
+			continue
+		}
+		t1 := (r.Min[i] - origin[i]) / dir[i]
+		t2 := (r.Max[i] - origin[i]) / dir[i]
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tMin {
+			tMin = t1
+			axis = i
+		}
+		if t2 < tMax {
+			tMax = t2
+		}
+	}
+	if tMin > tMax || tMax < 0 || tMin > dist {
+		return 0, 0, floatgeom.Point2{}, false
+	}
+	var normal floatgeom.Point2
+	if tMin < 0 {
+		tMin = 0
+	} else if axis != -1 {
+		normal[axis] = -math.Copysign(1, dir[axis])
+	}
+	if tMax > dist {
+		tMax = dist
+	}
+	return tMin, tMax, normal, true
+}
+
+// SearchRay satisfies RaySearcher. Only nodes whose bounding rectangles the
+// ray passes through are searched.
+func (tree *Rtree) SearchRay(origin, dir floatgeom.Point2, dist float64) []*Space {
+	return tree.searchRay(tree.root, origin, dir, dist, []*Space{})
+}
+
+func (tree *Rtree) searchRay(n *node, origin, dir floatgeom.Point2, dist float64, results []*Space) []*Space {
+	for _, e := range n.entries {
+		if _, _, _, ok := rayRect(origin, dir, dist, e.bb); !ok {
+			continue
+		}
+		if n.leaf {
+			results = append(results, e.obj)
+		} else {
+			results = tree.searchRay(e.child, origin, dir, dist, results)
+		}
+	}
+	return results
+}
@@ -0,0 +1,88 @@
+package collision
+
+import (
+	"math"
+	"testing"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRayHits(t *testing.T) {
+	for _, tb := range testBackends {
+		t.Run(tb.name, func(t *testing.T) {
+			tree, _ := NewBackendTree(tb.newLarge())
+			near := NewUnassignedSpace(10, -5, 5, 10)
+			// Thinner than any sampling step
+			thin := NewUnassignedSpace(20.5, -5, .01, 10)
+			far := NewUnassignedSpace(40, -1, 10, 2)
+			above := NewUnassignedSpace(20, -20, 5, 5)
+			inside := NewUnassignedSpace(-5, -5, 10, 10)
+			tree.Add(far, above, thin, near, inside)
+			// Filler, so the tree has several levels
+			for i := 0; i < 200; i++ {
+				tree.Add(NewUnassignedSpace(float64(i*10), 1000, 5, 5))
+			}
+
+			pts := tree.RayHits(floatgeom.Point2{0, 0}, floatgeom.Point2{2, 0}, 45)
+			assert.Len(t, pts, 4)
+			zones := []*Space{inside, near, thin, far}
+			for i, z := range zones {
+				assert.Equal(t, z, pts[i].Zone)
+			}
+			// Starting inside
+			assert.Equal(t, 0.0, pts[0].Distance)
+			assert.Equal(t, floatgeom.Point2{}, pts[0].Normal)
+			assert.Equal(t, 5.0, pts[0].ExitDistance)
+			// Entering and exiting
+			assert.Equal(t, 10.0, pts[1].X())
+			assert.Equal(t, 0.0, pts[1].Y())
+			assert.Equal(t, 10.0, pts[1].Distance)
+			assert.Equal(t, floatgeom.Point2{-1, 0}, pts[1].Normal)
+			assert.Equal(t, 15.0, pts[1].Exit.X())
+			assert.Equal(t, 15.0, pts[1].ExitDistance)
+			// Clamped to the cast distance
+			assert.Equal(t, 40.0, pts[3].Distance)
+			assert.Equal(t, 45.0, pts[3].ExitDistance)
+
+			// Upward, from below
+			pts = tree.RayHits(floatgeom.Point2{12, 30}, floatgeom.Point2{0, -1}, 100)
+			assert.Len(t, pts, 1)
+			assert.Equal(t, near, pts[0].Zone)
+			assert.Equal(t, floatgeom.Point2{0, 1}, pts[0].Normal)
+			assert.Equal(t, 25.0, pts[0].Distance)
+
+			// Diagonal
+			pts = tree.RayHits(floatgeom.Point2{0, 0}, floatgeom.Point2{1, -1}, 100)
+			assert.Len(t, pts, 2)
+			assert.Equal(t, inside, pts[0].Zone)
+			assert.Equal(t, above, pts[1].Zone)
+			assert.Equal(t, floatgeom.Point2{-1, 0}, pts[1].Normal)
+
+			// Infinite, along an axis
+			pts = tree.RayHits(floatgeom.Point2{0, 0}, floatgeom.Point2{1, 0}, math.Inf(1))
+			assert.Len(t, pts, 4)
+			assert.Equal(t, far, pts[3].Zone)
+			assert.Equal(t, 50.0, pts[3].ExitDistance)
+			pts = tree.RayHits(floatgeom.Point2{12, 30}, floatgeom.Point2{0, -1}, math.Inf(1))
+			assert.Len(t, pts, 1)
+			assert.Equal(t, near, pts[0].Zone)
+
+			assert.Empty(t, tree.RayHits(floatgeom.Point2{0, 0}, floatgeom.Point2{}, 100))
+			assert.Empty(t, tree.RayHits(floatgeom.Point2{0, 0}, floatgeom.Point2{1, 0}, 0))
+			assert.Empty(t, tree.RayHits(floatgeom.Point2{100, 0}, floatgeom.Point2{1, 0}, 100))
+		})
+	}
+}
+
+func TestRayBounds(t *testing.T) {
+	inf := math.Inf(1)
+	assert.Equal(t, floatgeom.NewRect3(0, 5, -inf, inf, 5, inf),
+		rayBounds(floatgeom.Point2{0, 5}, floatgeom.Point2{1, 0}, inf))
+	assert.Equal(t, floatgeom.NewRect3(3, -inf, -inf, 3, 1, inf),
+		rayBounds(floatgeom.Point2{3, 1}, floatgeom.Point2{0, -1}, inf))
+	assert.Equal(t, floatgeom.NewRect3(-inf, -inf, -inf, 1, 1, inf),
+		rayBounds(floatgeom.Point2{1, 1}, floatgeom.Point2{-.6, -.8}, inf))
+	assert.Equal(t, floatgeom.NewRect3(0, 0, -inf, 6, 8, inf),
+		rayBounds(floatgeom.Point2{0, 0}, floatgeom.Point2{.6, .8}, 10))
+}
@@ -7,6 +7,7 @@ package collision
 import (
 	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -690,59 +691,67 @@ func TestDelete(t *testing.T) {
 }
 
 func TestSearchIntersect(t *testing.T) {
-	rt := newTree(3, 3)
-	things := []*Space{
-		mustRect(floatgeom.Point3{0, 0}, [3]float64{2, 1}),
-		mustRect(floatgeom.Point3{3, 1}, [3]float64{1, 2}),
-		mustRect(floatgeom.Point3{1, 2}, [3]float64{2, 2}),
-		mustRect(floatgeom.Point3{8, 6}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{10, 3}, [3]float64{1, 2}),
-		mustRect(floatgeom.Point3{11, 7}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{2, 6}, [3]float64{1, 2}),
-		mustRect(floatgeom.Point3{3, 6}, [3]float64{1, 2}),
-		mustRect(floatgeom.Point3{2, 8}, [3]float64{1, 2}),
-		mustRect(floatgeom.Point3{3, 8}, [3]float64{1, 2}),
-	}
-	for _, thing := range things {
-		rt.Insert(thing)
-	}
+	for _, tb := range testBackends {
+		t.Run(tb.name, func(t *testing.T) {
+			rt := tb.new()
+			things := []*Space{
+				mustRect(floatgeom.Point3{0, 0}, [3]float64{2, 1}),
+				mustRect(floatgeom.Point3{3, 1}, [3]float64{1, 2}),
+				mustRect(floatgeom.Point3{1, 2}, [3]float64{2, 2}),
+				mustRect(floatgeom.Point3{8, 6}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{10, 3}, [3]float64{1, 2}),
+				mustRect(floatgeom.Point3{11, 7}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{2, 6}, [3]float64{1, 2}),
+				mustRect(floatgeom.Point3{3, 6}, [3]float64{1, 2}),
+				mustRect(floatgeom.Point3{2, 8}, [3]float64{1, 2}),
+				mustRect(floatgeom.Point3{3, 8}, [3]float64{1, 2}),
+			}
+			for _, thing := range things {
+				rt.Insert(thing)
+			}
 
-	bb := mustRect(floatgeom.Point3{2, 1.5}, [3]float64{10, 5.5})
-	q := rt.SearchIntersect(bb.Location)
+			bb := mustRect(floatgeom.Point3{2, 1.5}, [3]float64{10, 5.5})
+			q := rt.SearchIntersect(bb.Location)
 
-	expected := []int{1, 2, 3, 4, 6, 7}
-	if len(q) != len(expected) {
-		t.Errorf("SearchIntersect failed to find all objects")
-	}
-	for _, ind := range expected {
-		if indexOf(q, things[ind]) < 0 {
-			t.Errorf("SearchIntersect failed to find things[%d]", ind)
-		}
+			expected := []int{1, 2, 3, 4, 6, 7}
+			if len(q) != len(expected) {
+				t.Errorf("SearchIntersect failed to find all objects")
+			}
+			for _, ind := range expected {
+				if indexOf(q, things[ind]) < 0 {
+					t.Errorf("SearchIntersect failed to find things[%d]", ind)
+				}
+			}
+		})
 	}
 }
 
 func TestSearchIntersectNoResults(t *testing.T) {
-	rt := newTree(3, 3)
-	things := []*Space{
-		mustRect(floatgeom.Point3{0, 0}, [3]float64{2, 1}),
-		mustRect(floatgeom.Point3{3, 1}, [3]float64{1, 2}),
-		mustRect(floatgeom.Point3{1, 2}, [3]float64{2, 2}),
-		mustRect(floatgeom.Point3{8, 6}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{10, 3}, [3]float64{1, 2}),
-		mustRect(floatgeom.Point3{11, 7}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{2, 6}, [3]float64{1, 2}),
-		mustRect(floatgeom.Point3{3, 6}, [3]float64{1, 2}),
-		mustRect(floatgeom.Point3{2, 8}, [3]float64{1, 2}),
-		mustRect(floatgeom.Point3{3, 8}, [3]float64{1, 2}),
-	}
-	for _, thing := range things {
-		rt.Insert(thing)
-	}
+	for _, tb := range testBackends {
+		t.Run(tb.name, func(t *testing.T) {
+			rt := tb.new()
+			things := []*Space{
+				mustRect(floatgeom.Point3{0, 0}, [3]float64{2, 1}),
+				mustRect(floatgeom.Point3{3, 1}, [3]float64{1, 2}),
+				mustRect(floatgeom.Point3{1, 2}, [3]float64{2, 2}),
+				mustRect(floatgeom.Point3{8, 6}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{10, 3}, [3]float64{1, 2}),
+				mustRect(floatgeom.Point3{11, 7}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{2, 6}, [3]float64{1, 2}),
+				mustRect(floatgeom.Point3{3, 6}, [3]float64{1, 2}),
+				mustRect(floatgeom.Point3{2, 8}, [3]float64{1, 2}),
+				mustRect(floatgeom.Point3{3, 8}, [3]float64{1, 2}),
+			}
+			for _, thing := range things {
+				rt.Insert(thing)
+			}
 
-	bb := mustRect(floatgeom.Point3{99, 99}, [3]float64{10, 5.5})
-	q := rt.SearchIntersect(bb.Location)
-	if len(q) != 0 {
-		t.Errorf("SearchIntersect failed to return nil slice on failing query")
+			bb := mustRect(floatgeom.Point3{99, 99}, [3]float64{10, 5.5})
+			q := rt.SearchIntersect(bb.Location)
+			if len(q) != 0 {
+				t.Errorf("SearchIntersect failed to return nil slice on failing query")
+			}
+		})
 	}
 }
 
@@ -767,91 +776,170 @@ func TestSortEntries(t *testing.T) {
 }
 
 func TestNearestNeighbor(t *testing.T) {
-	rt := newTree(3, 3)
-	things := []*Space{
-		mustRect(floatgeom.Point3{1, 1}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{1, 3}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{3, 2}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{-7, -7}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{7, 7}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{10, 2}, [3]float64{1, 1}),
-	}
-	for _, thing := range things {
-		rt.Insert(thing)
-	}
+	for _, tb := range testBackends {
+		t.Run(tb.name, func(t *testing.T) {
+			rt := tb.new()
+			things := []*Space{
+				mustRect(floatgeom.Point3{1, 1}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{1, 3}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{3, 2}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{-7, -7}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{7, 7}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{10, 2}, [3]float64{1, 1}),
+			}
+			for _, thing := range things {
+				rt.Insert(thing)
+			}
 
-	obj1 := rt.NearestNeighbor(floatgeom.Point3{0.5, 0.5})
-	obj2 := rt.NearestNeighbor(floatgeom.Point3{1.5, 4.5})
-	obj3 := rt.NearestNeighbor(floatgeom.Point3{5, 2.5})
-	obj4 := rt.NearestNeighbor(floatgeom.Point3{3.5, 2.5})
+			obj1 := rt.NearestNeighbor(floatgeom.Point3{0.5, 0.5})
+			obj2 := rt.NearestNeighbor(floatgeom.Point3{1.5, 4.5})
+			obj3 := rt.NearestNeighbor(floatgeom.Point3{5, 2.5})
+			obj4 := rt.NearestNeighbor(floatgeom.Point3{3.5, 2.5})
 
-	if obj1 != things[0] || obj2 != things[1] || obj3 != things[2] || obj4 != things[2] {
-		t.Errorf("NearestNeighbor failed")
+			if obj1 != things[0] || obj2 != things[1] || obj3 != things[2] || obj4 != things[2] {
+				t.Errorf("NearestNeighbor failed")
+			}
+		})
 	}
 }
 
 func TestNearestNeighbors(t *testing.T) {
-	rt := newTree(3, 3)
-	things := []*Space{
-		mustRect(floatgeom.Point3{1, 1}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{-7, -7}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{1, 3}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{7, 7}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{10, 2}, [3]float64{1, 1}),
-		mustRect(floatgeom.Point3{3, 3}, [3]float64{1, 1}),
-	}
-	for _, thing := range things {
-		rt.Insert(thing)
-	}
+	for _, tb := range testBackends {
+		t.Run(tb.name, func(t *testing.T) {
+			rt := tb.new()
+			things := []*Space{
+				mustRect(floatgeom.Point3{1, 1}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{-7, -7}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{1, 3}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{7, 7}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{10, 2}, [3]float64{1, 1}),
+				mustRect(floatgeom.Point3{3, 3}, [3]float64{1, 1}),
+			}
+			for _, thing := range things {
+				rt.Insert(thing)
+			}
 
-	objs := rt.NearestNeighbors(3, floatgeom.Point3{0.5, 0.5})
-	if objs[0] != things[0] || objs[1] != things[2] || objs[2] != things[5] {
-		t.Errorf("NearestNeighbors failed")
+			objs := rt.NearestNeighbors(3, floatgeom.Point3{0.5, 0.5})
+			if objs[0] != things[0] || objs[1] != things[2] || objs[2] != things[5] {
+				t.Errorf("NearestNeighbors failed")
+			}
+		})
 	}
 }
 
 func BenchmarkSearchIntersect(b *testing.B) {
-	b.StopTimer()
-	rt := newTree(3, 3)
-	things := []*Space{
-		mustRect(floatgeom.Point3{0, 0, 0}, [3]float64{2, 1, 1}),
-		mustRect(floatgeom.Point3{3, 1, 0}, [3]float64{1, 2, 1}),
-		mustRect(floatgeom.Point3{1, 2, 0}, [3]float64{2, 2, 1}),
-		mustRect(floatgeom.Point3{8, 6, 0}, [3]float64{1, 1, 1}),
-		mustRect(floatgeom.Point3{10, 3, 0}, [3]float64{1, 2, 1}),
-		mustRect(floatgeom.Point3{11, 7, 0}, [3]float64{1, 1, 1}),
-		mustRect(floatgeom.Point3{2, 6, 0}, [3]float64{1, 2, 1}),
-		mustRect(floatgeom.Point3{3, 6, 0}, [3]float64{1, 2, 1}),
-		mustRect(floatgeom.Point3{2, 8, 0}, [3]float64{1, 2, 1}),
-		mustRect(floatgeom.Point3{3, 8, 0}, [3]float64{1, 2, 1}),
+	for _, tb := range testBackends {
+		b.Run(tb.name, func(b *testing.B) {
+			rt := tb.new()
+			things := []*Space{
+				mustRect(floatgeom.Point3{0, 0, 0}, [3]float64{2, 1, 1}),
+				mustRect(floatgeom.Point3{3, 1, 0}, [3]float64{1, 2, 1}),
+				mustRect(floatgeom.Point3{1, 2, 0}, [3]float64{2, 2, 1}),
+				mustRect(floatgeom.Point3{8, 6, 0}, [3]float64{1, 1, 1}),
+				mustRect(floatgeom.Point3{10, 3, 0}, [3]float64{1, 2, 1}),
+				mustRect(floatgeom.Point3{11, 7, 0}, [3]float64{1, 1, 1}),
+				mustRect(floatgeom.Point3{2, 6, 0}, [3]float64{1, 2, 1}),
+				mustRect(floatgeom.Point3{3, 6, 0}, [3]float64{1, 2, 1}),
+				mustRect(floatgeom.Point3{2, 8, 0}, [3]float64{1, 2, 1}),
+				mustRect(floatgeom.Point3{3, 8, 0}, [3]float64{1, 2, 1}),
+			}
+			for _, thing := range things {
+				rt.Insert(thing)
+			}
+			bb := mustRect(floatgeom.Point3{2, 1.5, 0}, [3]float64{10, 5.5, 1})
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rt.SearchIntersect(bb.Location)
+			}
+		})
 	}
-	for _, thing := range things {
-		rt.Insert(thing)
+}
+
+func BenchmarkInsert(b *testing.B) {
+	for _, tb := range testBackends {
+		b.Run(tb.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rt := tb.new()
+				things := []*Space{
+					mustRect(floatgeom.Point3{0, 0, 0}, [3]float64{2, 1, 1}),
+					mustRect(floatgeom.Point3{3, 1, 0}, [3]float64{1, 2, 1}),
+					mustRect(floatgeom.Point3{1, 2, 0}, [3]float64{2, 2, 1}),
+					mustRect(floatgeom.Point3{8, 6, 0}, [3]float64{1, 1, 1}),
+					mustRect(floatgeom.Point3{10, 3, 0}, [3]float64{1, 2, 1}),
+					mustRect(floatgeom.Point3{11, 7, 0}, [3]float64{1, 1, 1}),
+					mustRect(floatgeom.Point3{2, 6, 0}, [3]float64{1, 2, 1}),
+					mustRect(floatgeom.Point3{3, 6, 0}, [3]float64{1, 2, 1}),
+					mustRect(floatgeom.Point3{2, 8, 0}, [3]float64{1, 2, 1}),
+					mustRect(floatgeom.Point3{3, 8, 0}, [3]float64{1, 2, 1}),
+				}
+				for _, thing := range things {
+					rt.Insert(thing)
+				}
+			}
+		})
 	}
-	bb := mustRect(floatgeom.Point3{2, 1.5, 0}, [3]float64{10, 5.5, 1})
-	b.StartTimer()
-	for i := 0; i < b.N; i++ {
-		rt.SearchIntersect(bb.Location)
+}
+
+func BenchmarkLoad(b *testing.B) {
+	sps := make([]*Space, 20000)
+	for i := range sps {
+		sps[i] = randomSpace()
+	}
+	b.Run("Add", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tree, _ := NewTree()
+			tree.Add(sps...)
+		}
+	})
+	b.Run("Load", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewBulkTree(sps)
+		}
+	})
+}
+
+func BenchmarkLoadedSearchIntersect(b *testing.B) {
+	sps := make([]*Space, 20000)
+	for i := range sps {
+		sps[i] = randomSpace()
+	}
+	added, _ := NewTree()
+	added.Add(sps...)
+	loaded, _ := NewBulkTree(sps)
+	for _, tree := range []struct {
+		name string
+		*Tree
+	}{{"Add", added}, {"Load", loaded}} {
+		b.Run(tree.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree.SearchIntersect(randomSpace().Location)
+			}
+		})
 	}
 }
 
-func BenchmarkInsert(b *testing.B) {
-	for i := 0; i < b.N; i++ {
-		rt := newTree(3, 3)
-		things := []*Space{
-			mustRect(floatgeom.Point3{0, 0, 0}, [3]float64{2, 1, 1}),
-			mustRect(floatgeom.Point3{3, 1, 0}, [3]float64{1, 2, 1}),
-			mustRect(floatgeom.Point3{1, 2, 0}, [3]float64{2, 2, 1}),
-			mustRect(floatgeom.Point3{8, 6, 0}, [3]float64{1, 1, 1}),
-			mustRect(floatgeom.Point3{10, 3, 0}, [3]float64{1, 2, 1}),
-			mustRect(floatgeom.Point3{11, 7, 0}, [3]float64{1, 1, 1}),
-			mustRect(floatgeom.Point3{2, 6, 0}, [3]float64{1, 2, 1}),
-			mustRect(floatgeom.Point3{3, 6, 0}, [3]float64{1, 2, 1}),
-			mustRect(floatgeom.Point3{2, 8, 0}, [3]float64{1, 2, 1}),
-			mustRect(floatgeom.Point3{3, 8, 0}, [3]float64{1, 2, 1}),
+func BenchmarkUpdateSpaces(b *testing.B) {
+	for _, count := range []int{100, 5000} {
+		sps := make([]*Space, 20000)
+		for i := range sps {
+			sps[i] = randomSpace()
 		}
-		for _, thing := range things {
-			rt.Insert(thing)
+		tree, _ := NewBulkTree(sps)
+		updates := make([]SpaceUpdate, count)
+		for i := range updates {
+			updates[i] = SpaceUpdate{Space: sps[i], Location: sps[i].Location}
 		}
+		b.Run("UpdateSpaceRect/"+strconv.Itoa(count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, u := range updates {
+					tree.UpdateSpaceRect(u.Location, u.Space)
+				}
+			}
+		})
+		b.Run("UpdateSpaces/"+strconv.Itoa(count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree.UpdateSpaces(updates...)
+			}
+		})
 	}
 }
@@ -0,0 +1,352 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+)
+
+// A Shape is an outline a space can have within its rectangle. Shapes are
+// positioned relative to the minimum corner of their space's Location, so
+// they move with their space, but they are not resized alongside it.
+//
+// Spaces without a shape are just their rectangle. Trees find spaces by
+// their rectangles, then check the shapes of those spaces against each
+// other, so a shape should stay within its space's rectangle.
+type Shape interface {
+	// Bounds returns the bounding rectangle of the shape relative
+	// to its space.
+	Bounds() floatgeom.Rect2
+	// core returns the points of a convex polygon, line, or point,
+	// which when expanded by the returned radius forms the shape.
+	core() ([]floatgeom.Point2, float64)
+}
+
+// A Circle is a Shape with a center and radius.
+type Circle struct {
+	Center floatgeom.Point2
+	Radius float64
+}
+
+// Bounds satisfies Shape
+func (c Circle) Bounds() floatgeom.Rect2 {
+	return floatgeom.NewRect2(
+		c.Center.X()-c.Radius, c.Center.Y()-c.Radius,
+		c.Center.X()+c.Radius, c.Center.Y()+c.Radius,
+	)
+}
+
+func (c Circle) core() ([]floatgeom.Point2, float64) {
+	return []floatgeom.Point2{c.Center}, c.Radius
+}
+
+// A Polygon is a Shape made of the points of a convex polygon, in either
+// winding order.
+type Polygon struct {
+	Points []floatgeom.Point2
+}
+
+// Bounds satisfies Shape
+func (p Polygon) Bounds() floatgeom.Rect2 {
+	return floatgeom.NewBoundingRect2(p.Points...)
+}
+
+func (p Polygon) core() ([]floatgeom.Point2, float64) {
+	return p.Points, 0
+}
+
+// A Capsule is a Shape formed of every point within Radius of the line
+// from A to B.
+type Capsule struct {
+	A, B   floatgeom.Point2
+	Radius float64
+}
+
+// Bounds satisfies Shape
+func (c Capsule) Bounds() floatgeom.Rect2 {
+	r := floatgeom.Point2{c.Radius, c.Radius}
+	return floatgeom.NewBoundingRect2(c.A.Sub(r), c.A.Add(r), c.B.Sub(r), c.B.Add(r))
+}
+
+func (c Capsule) core() ([]floatgeom.Point2, float64) {
+	return []floatgeom.Point2{c.A, c.B}, c.Radius
+}
+
+// NewCircleSpace returns a space with a circle shape centered at (x,y)
+func NewCircleSpace(x, y, r float64, l Label, cID event.CID) *Space {
+	return newShapeSpace(Circle{
+		Center: floatgeom.Point2{x, y},
+		Radius: r,
+	}, l, cID)
+}
+
+// NewPolygonSpace returns a space with the given convex polygon as its
+// shape. At least three points must be given.
+func NewPolygonSpace(pts []floatgeom.Point2, l Label, cID event.CID) (*Space, error) {
+	if len(pts) < 3 {
+		return nil, oakerr.InsufficientInputs{AtLeast: 3, InputName: "pts"}
+	}
+	if !isConvex(pts) {
+		return nil, oakerr.InvalidInput{InputName: "pts"}
+	}
+	cp := make([]floatgeom.Point2, len(pts))
+	copy(cp, pts)
+	return newShapeSpace(Polygon{Points: cp}, l, cID), nil
+}
+
+// NewCapsuleSpace returns a space with a capsule shape around the
+// line from a to b
+func NewCapsuleSpace(a, b floatgeom.Point2, r float64, l Label, cID event.CID) *Space {
+	return newShapeSpace(Capsule{
+		A:      a,
+		B:      b,
+		Radius: r,
+	}, l, cID)
+}
+
+// newShapeSpace creates a space around a shape given in absolute
+// coordinates, then makes the shape relative to that space.
+func newShapeSpace(shape Shape, l Label, cID event.CID) *Space {
+	b := shape.Bounds()
+	sp := NewRectSpace(NewRect(b.Min.X(), b.Min.Y(), b.W(), b.H()), l, cID)
+	sp.Shape = offsetShape(shape, b.Min.MulConst(-1))
+	return sp
+}
+
+func offsetShape(shape Shape, off floatgeom.Point2) Shape {
+	switch s := shape.(type) {
+	case Circle:
+		s.Center = s.Center.Add(off)
+		return s
+	case Capsule:
+		s.A = s.A.Add(off)
+		s.B = s.B.Add(off)
+		return s
+	case Polygon:
+		for i, p := range s.Points {
+			s.Points[i] = p.Add(off)
+		}
+		return s
+	}
+	return shape
+}
+
+func isConvex(pts []floatgeom.Point2) bool {
+	sign := 0.0
+	for i := range pts {
+		a := pts[i]
+		b := pts[(i+1)%len(pts)]
+		c := pts[(i+2)%len(pts)]
+		turn := cross(b.Sub(a), c.Sub(b))
+		if turn == 0 {
+			continue
+		}
+		if sign == 0 {
+			sign = turn
+		} else if (turn > 0) != (sign > 0) {
+			return false
+		}
+	}
+	// All points on one line
+	return sign != 0
+}
+
+func cross(a, b floatgeom.Point2) float64 {
+	return a.X()*b.Y() - a.Y()*b.X()
+}
+
+// corePoints returns the absolute points and radius of a space's shape,
+// or the corners of its rectangle if it has no shape.
+func (s *Space) corePoints() ([]floatgeom.Point2, float64) {
+	min := floatgeom.Point2{s.X(), s.Y()}
+	if s.Shape == nil {
+		max := floatgeom.Point2{s.Location.Max.X(), s.Location.Max.Y()}
+		return []floatgeom.Point2{
+			min,
+			{max.X(), min.Y()},
+			max,
+			{min.X(), max.Y()},
+		}, 0
+	}
+	pts, r := s.Shape.core()
+	abs := make([]floatgeom.Point2, len(pts))
+	for i, p := range pts {
+		abs[i] = p.Add(min)
+	}
+	return abs, r
+}
+
+// MTV returns the minimum translation vector that would move this space out
+// of other, and whether the spaces overlap. Spaces which only touch do not
+// overlap. The shapes of both spaces are used, if they have them.
+func (s *Space) MTV(other *Space) (physics.Vector, bool) {
+	if !s.Location.Intersects(other.Location) {
+		return physics.NewVector(0, 0), false
+	}
+	a, ra := s.corePoints()
+	b, rb := other.corePoints()
+	mtv, ok := sat(a, b, ra, rb)
+	return physics.NewVector(mtv.X(), mtv.Y()), ok
+}
+
+// narrowHit returns whether two spaces whose rectangles intersect
+// also overlap by their shapes.
+func narrowHit(s, other *Space) bool {
+	if s.Shape == nil && other.Shape == nil {
+		return true
+	}
+	_, ok := s.MTV(other)
+	return ok
+}
+
+// narrowHits filters spaces whose rectangles intersect s to those
+// which also overlap s by their shapes.
+func narrowHits(s *Space, results []*Space) []*Space {
+	out := results[:0]
+	for _, v := range results {
+		if v == nil || v == s || narrowHit(s, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// sat runs the separating axis test on two convex polygons, lines, or
+// points, each expanded by a radius. If they overlap, it returns the
+// smallest vector which would move a out of b.
+func sat(a, b []floatgeom.Point2, ra, rb float64) (floatgeom.Point2, bool) {
+	var axes []floatgeom.Point2
+	axes = appendEdgeNormals(axes, a)
+	axes = appendEdgeNormals(axes, b)
+	if ra != 0 || rb != 0 {
+		axes = appendClosestAxes(axes, a, b)
+		axes = appendClosestAxes(axes, b, a)
+	}
+	if len(axes) == 0 {
+		// Two concentric circles
+		axes = append(axes, floatgeom.Point2{1, 0})
+	}
+	best := math.Inf(1)
+	var mtv floatgeom.Point2
+	for _, axis := range axes {
+		minA, maxA := project(a, axis)
+		minB, maxB := project(b, axis)
+		minA, maxA = minA-ra, maxA+ra
+		minB, maxB = minB-rb, maxB+rb
+		overlap := math.Min(maxA, maxB) - math.Max(minA, minB)
+		if overlap <= 0 {
+			return floatgeom.Point2{}, false
+		}
+		if overlap < best {
+			best = overlap
+			if minA+maxA < minB+maxB {
+				mtv = axis.MulConst(-overlap)
+			} else {
+				mtv = axis.MulConst(overlap)
+			}
+		}
+	}
+	return mtv, true
+}
+
+func appendEdgeNormals(axes, pts []floatgeom.Point2) []floatgeom.Point2 {
+	if len(pts) < 2 {
+		return axes
+	}
+	n := len(pts)
+	if n == 2 {
+		// A line has only one edge
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		edge := pts[(i+1)%len(pts)].Sub(pts[i])
+		axes = appendAxis(axes, floatgeom.Point2{-edge.Y(), edge.X()})
+	}
+	return axes
+}
+
+// appendClosestAxes appends the axes from each point in a to the closest
+// point to it in b.
+func appendClosestAxes(axes, a, b []floatgeom.Point2) []floatgeom.Point2 {
+	for _, p := range a {
+		axes = appendAxis(axes, p.Sub(closestPoint(p, b)))
+	}
+	return axes
+}
+
+func appendAxis(axes []floatgeom.Point2, axis floatgeom.Point2) []floatgeom.Point2 {
+	if axis.X() == 0 && axis.Y() == 0 {
+		return axes
+	}
+	return append(axes, axis.Normalize())
+}
+
+// closestPoint returns the closest point to p on the outline of a convex
+// polygon, line, or point. If p is within the polygon, p is returned.
+func closestPoint(p floatgeom.Point2, pts []floatgeom.Point2) floatgeom.Point2 {
+	if len(pts) == 1 {
+		return pts[0]
+	}
+	if len(pts) > 2 && polygonContains(pts, p) {
+		return p
+	}
+	var closest floatgeom.Point2
+	best := math.Inf(1)
+	n := len(pts)
+	if n == 2 {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		c := closestOnSegment(p, pts[i], pts[(i+1)%len(pts)])
+		if d := c.Sub(p).Magnitude(); d < best {
+			best = d
+			closest = c
+		}
+	}
+	return closest
+}
+
+func closestOnSegment(p, a, b floatgeom.Point2) floatgeom.Point2 {
+	ab := b.Sub(a)
+	lenSq := ab.Dot(ab)
+	if lenSq == 0 {
+		return a
+	}
+	t := p.Sub(a).Dot(ab) / lenSq
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+	return a.Add(ab.MulConst(t))
+}
+
+func polygonContains(pts []floatgeom.Point2, p floatgeom.Point2) bool {
+	sign := 0.0
+	for i := range pts {
+		c := cross(pts[(i+1)%len(pts)].Sub(pts[i]), p.Sub(pts[i]))
+		if c == 0 {
+			continue
+		}
+		if sign == 0 {
+			sign = c
+		} else if (c > 0) != (sign > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func project(pts []floatgeom.Point2, axis floatgeom.Point2) (float64, float64) {
+	min := math.Inf(1)
+	max := math.Inf(-1)
+	for _, p := range pts {
+		d := p.Dot(axis)
+		min = math.Min(min, d)
+		max = math.Max(max, d)
+	}
+	return min, max
+}
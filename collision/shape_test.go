@@ -0,0 +1,126 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShapeSpaces(t *testing.T) {
+	c := NewCircleSpace(10, 10, 5, 1, 0)
+	assert.Equal(t, 5.0, c.X())
+	assert.Equal(t, 5.0, c.Y())
+	assert.Equal(t, 10.0, c.GetW())
+	assert.Equal(t, Circle{Center: floatgeom.Point2{5, 5}, Radius: 5}, c.Shape)
+
+	cp := NewCapsuleSpace(floatgeom.Point2{10, 10}, floatgeom.Point2{30, 10}, 2, 1, 0)
+	assert.Equal(t, 8.0, cp.X())
+	assert.Equal(t, 24.0, cp.GetW())
+	assert.Equal(t, 4.0, cp.GetH())
+
+	_, err := NewPolygonSpace([]floatgeom.Point2{{0, 0}, {1, 1}}, 1, 0)
+	assert.Equal(t, oakerr.InsufficientInputs{AtLeast: 3, InputName: "pts"}, err)
+	_, err = NewPolygonSpace([]floatgeom.Point2{{0, 0}, {1, 1}, {2, 2}}, 1, 0)
+	assert.Equal(t, oakerr.InvalidInput{InputName: "pts"}, err)
+	_, err = NewPolygonSpace([]floatgeom.Point2{{0, 0}, {10, 0}, {5, 2}, {10, 10}, {0, 10}}, 1, 0)
+	assert.Equal(t, oakerr.InvalidInput{InputName: "pts"}, err)
+	p, err := NewPolygonSpace([]floatgeom.Point2{{10, 10}, {20, 10}, {10, 20}}, 1, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 10.0, p.X())
+	assert.Equal(t, Polygon{Points: []floatgeom.Point2{{0, 0}, {10, 0}, {0, 10}}}, p.Shape)
+}
+
+func TestMTV(t *testing.T) {
+	type testCase struct {
+		name     string
+		a, b     *Space
+		overlaps bool
+		x, y     float64
+	}
+	tri, _ := NewPolygonSpace([]floatgeom.Point2{{0, 0}, {10, 0}, {0, 10}}, 0, 0)
+	tests := []testCase{
+		{
+			name:     "rects",
+			a:        NewUnassignedSpace(0, 0, 10, 10),
+			b:        NewUnassignedSpace(8, 0, 10, 10),
+			overlaps: true,
+			x:        -2,
+		}, {
+			name: "touching rects",
+			a:    NewUnassignedSpace(0, 0, 10, 10),
+			b:    NewUnassignedSpace(10, 0, 10, 10),
+		}, {
+			name:     "circles",
+			a:        NewCircleSpace(0, 0, 5, 0, 0),
+			b:        NewCircleSpace(0, 8, 5, 0, 0),
+			overlaps: true,
+			y:        -2,
+		}, {
+			name: "circle in rectangle corner",
+			a:    NewCircleSpace(0, 0, 5, 0, 0),
+			b:    NewUnassignedSpace(4, 4, 10, 10),
+		}, {
+			name:     "circle against rectangle side",
+			a:        NewCircleSpace(0, 5, 5, 0, 0),
+			b:        NewUnassignedSpace(4, 0, 10, 10),
+			overlaps: true,
+			x:        -1,
+		}, {
+			name: "rectangle beyond triangle hypotenuse",
+			a:    tri,
+			b:    NewUnassignedSpace(6, 6, 4, 4),
+		}, {
+			name:     "rectangle in triangle",
+			a:        tri,
+			b:        NewUnassignedSpace(1, 1, 2, 2),
+			overlaps: true,
+		}, {
+			name:     "capsules",
+			a:        NewCapsuleSpace(floatgeom.Point2{0, 0}, floatgeom.Point2{20, 0}, 2, 0, 0),
+			b:        NewCapsuleSpace(floatgeom.Point2{10, 3}, floatgeom.Point2{10, 20}, 2, 0, 0),
+			overlaps: true,
+			y:        -1,
+		}, {
+			name: "separated capsules",
+			a:    NewCapsuleSpace(floatgeom.Point2{0, 0}, floatgeom.Point2{20, 20}, 1, 0, 0),
+			b:    NewCapsuleSpace(floatgeom.Point2{0, 10}, floatgeom.Point2{8, 18}, 1, 0, 0),
+		}, {
+			name:     "concentric circles",
+			a:        NewCircleSpace(0, 0, 5, 0, 0),
+			b:        NewCircleSpace(0, 0, 3, 0, 0),
+			overlaps: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mtv, ok := tc.a.MTV(tc.b)
+			assert.Equal(t, tc.overlaps, ok)
+			if tc.x != 0 || tc.y != 0 {
+				assert.InDelta(t, tc.x, mtv.X(), .0001)
+				assert.InDelta(t, tc.y, mtv.Y(), .0001)
+			}
+		})
+	}
+}
+
+func TestTreeShapes(t *testing.T) {
+	tree, _ := NewTree()
+	circle := NewCircleSpace(0, 0, 5, 1, 0)
+	tree.Add(circle)
+
+	corner := NewLabeledSpace(4, 4, 10, 10, 2)
+	assert.Empty(t, tree.Hits(corner))
+	assert.Nil(t, tree.HitLabel(corner, 1))
+	assert.Empty(t, tree.Hit(corner))
+	assert.Empty(t, tree.Contacts(corner))
+
+	side := NewLabeledSpace(4, -2, 10, 4, 2)
+	assert.Len(t, tree.Hits(side), 1)
+	assert.Equal(t, circle, tree.HitLabel(side, 1))
+	contacts := tree.Contacts(side)
+	assert.Len(t, contacts, 1)
+	assert.Equal(t, circle, contacts[0].Space)
+	assert.InDelta(t, 1.0, contacts[0].MTV.X(), .0001)
+}
@@ -16,8 +16,8 @@ const (
 	PID
 )
 
-// A Space is a rectangle
-// with a couple of ways of identifying
+// A Space is a rectangle, optionally with a more
+// specific Shape, with a couple of ways of identifying
 // an underlying object.
 type Space struct {
 	Location floatgeom.Rect3
@@ -30,6 +30,8 @@ type Space struct {
 	// Type represents which ID space the above ID
 	// corresponds to.
 	Type int
+	// Shape, if set, is the outline of the space within Location.
+	Shape Shape
 }
 
 // Bounds satisfies the rtreego.Spatial interface.
@@ -91,6 +93,16 @@ func (s *Space) Contains(other *Space) bool {
 	return true
 }
 
+// HasLabel returns whether this space's label is one of the given labels.
+func (s *Space) HasLabel(ls ...Label) bool {
+	for _, l := range ls {
+		if s.Label == l {
+			return true
+		}
+	}
+	return false
+}
+
 // LeftOf returns how far to the left other is of this space
 func (s *Space) LeftOf(other *Space) float64 {
 	return other.X() - s.X()
@@ -214,10 +226,10 @@ func NewLabeledSpace(x, y, w, h float64, l Label) *Space {
 func NewFullSpace(x, y, w, h float64, l Label, cID event.CID) *Space {
 	rect := NewRect(x, y, w, h)
 	return &Space{
-		rect,
-		l,
-		cID,
-		CID, // todo: This is hard to read as distinct from cID
+		Location: rect,
+		Label:    l,
+		CID:      cID,
+		Type:     CID, // todo: This is hard to read as distinct from cID
 		// todo: a way to generate non-CID typed spaces that isn't
 		// package specific (see render/particle)
 	}
@@ -226,10 +238,10 @@ func NewFullSpace(x, y, w, h float64, l Label, cID event.CID) *Space {
 // NewRectSpace creates a colliison space with the specified 3D rectangle
 func NewRectSpace(rect floatgeom.Rect3, l Label, cID event.CID) *Space {
 	return &Space{
-		rect,
-		l,
-		cID,
-		CID,
+		Location: rect,
+		Label:    l,
+		CID:      cID,
+		Type:     CID,
 	}
 }
 
@@ -27,6 +27,12 @@ func TestSpaceFuncs(t *testing.T) {
 	assert.True(t, s2.LeftOf(s) < 0)
 	assert.True(t, s2.RightOf(s) > 0)
 
+	// Labels
+	s.Label = 2
+	assert.True(t, s.HasLabel(1, 2))
+	assert.False(t, s.HasLabel(1, 3))
+	assert.False(t, s.HasLabel())
+
 	// Containment
 	assert.False(t, s2.Contains(s))
 	s3 := NewUnassignedSpace(5, 5, 20, 20)
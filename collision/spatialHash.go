@@ -0,0 +1,313 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/oakerr"
+)
+
+// A SpatialHash is a Backend which divides the plane into a uniform grid
+// of cells, storing each space in every cell its rectangle overlaps. Moving
+// a space only touches the cells it leaves and enters, which makes spatial
+// hashes faster than Rtrees for many small spaces which move every frame.
+// Spaces much larger than a cell are slow to store and move.
+//
+// Only the x and y dimensions of spaces are used to place them in cells.
+type SpatialHash struct {
+	cellW, cellH float64
+	cells        map[hashCell][]*Space
+	// spaces stores the cells each space was inserted into
+	spaces map[*Space]cellRange
+}
+
+type hashCell struct {
+	x, y int
+}
+
+// cellRange is an inclusive range of cells
+type cellRange struct {
+	min, max hashCell
+}
+
+// NewSpatialHash returns an empty SpatialHash with cells of the given
+// width and height. Cells should be around the size of the spaces stored
+// in the hash.
+func NewSpatialHash(cellW, cellH float64) (*SpatialHash, error) {
+	if cellW <= 0 || math.IsInf(cellW, 0) || math.IsNaN(cellW) {
+		return nil, oakerr.InvalidInput{InputName: "cellW"}
+	}
+	if cellH <= 0 || math.IsInf(cellH, 0) || math.IsNaN(cellH) {
+		return nil, oakerr.InvalidInput{InputName: "cellH"}
+	}
+	return &SpatialHash{
+		cellW:  cellW,
+		cellH:  cellH,
+		cells:  make(map[hashCell][]*Space),
+		spaces: make(map[*Space]cellRange),
+	}, nil
+}
+
+// CellSize returns the width and height of the hash's cells.
+func (h *SpatialHash) CellSize() (float64, float64) {
+	return h.cellW, h.cellH
+}
+
+// Len returns how many spaces are stored in the hash.
+func (h *SpatialHash) Len() int {
+	return len(h.spaces)
+}
+
+func (h *SpatialHash) cellOf(x, y float64) hashCell {
+	return hashCell{
+		x: int(math.Floor(x / h.cellW)),
+		y: int(math.Floor(y / h.cellH)),
+	}
+}
+
+func (h *SpatialHash) cellsOf(r floatgeom.Rect3) cellRange {
+	return cellRange{
+		min: h.cellOf(r.Min.X(), r.Min.Y()),
+		max: h.cellOf(r.Max.X(), r.Max.Y()),
+	}
+}
+
+func (cr cellRange) size() int {
+	return (cr.max.x - cr.min.x + 1) * (cr.max.y - cr.min.y + 1)
+}
+
+// maxSearchCell bounds the cell coordinates which SearchIntersect will
+// visit, so that cell ranges and their sizes cannot overflow.
+const maxSearchCell = 1 << 30
+
+// searchCells returns the cells which bb overlaps, and whether checking
+// those cells is worthwhile. It is not when bb is not finite, or when it
+// covers more cells than there are spaces.
+func (h *SpatialHash) searchCells(bb floatgeom.Rect3) (cellRange, bool) {
+	bounds := [4]float64{
+		math.Floor(bb.Min.X() / h.cellW),
+		math.Floor(bb.Min.Y() / h.cellH),
+		math.Floor(bb.Max.X() / h.cellW),
+		math.Floor(bb.Max.Y() / h.cellH),
+	}
+	for _, b := range bounds {
+		// This also rejects NaN bounds
+		if !(math.Abs(b) <= maxSearchCell) {
+			return cellRange{}, false
+		}
+	}
+	cr := cellRange{
+		min: hashCell{int(bounds[0]), int(bounds[1])},
+		max: hashCell{int(bounds[2]), int(bounds[3])},
+	}
+	size := (bounds[2] - bounds[0] + 1) * (bounds[3] - bounds[1] + 1)
+	return cr, size <= float64(len(h.spaces))
+}
+
+// Insert satisfies Backend. Inserting a space which is already in the
+// hash moves it to its current Location.
+func (h *SpatialHash) Insert(sp *Space) {
+	if cr, ok := h.spaces[sp]; ok {
+		h.removeFrom(sp, cr)
+	}
+	cr := h.cellsOf(sp.Location)
+	h.spaces[sp] = cr
+	h.addTo(sp, cr)
+}
+
+// Delete satisfies Backend
+func (h *SpatialHash) Delete(sp *Space) bool {
+	cr, ok := h.spaces[sp]
+	if !ok {
+		return false
+	}
+	h.removeFrom(sp, cr)
+	delete(h.spaces, sp)
+	return true
+}
+
+// Update satisfies Backend. Only cells which sp leaves or enters are
+// modified.
+func (h *SpatialHash) Update(sp *Space, rect floatgeom.Rect3) {
+	sp.Location = rect
+	old, ok := h.spaces[sp]
+	cr := h.cellsOf(rect)
+	h.spaces[sp] = cr
+	if !ok {
+		h.addTo(sp, cr)
+		return
+	}
+	if old == cr {
+		return
+	}
+	for x := old.min.x; x <= old.max.x; x++ {
+		for y := old.min.y; y <= old.max.y; y++ {
+			if !cr.contains(hashCell{x, y}) {
+				h.removeFromCell(sp, hashCell{x, y})
+			}
+		}
+	}
+	for x := cr.min.x; x <= cr.max.x; x++ {
+		for y := cr.min.y; y <= cr.max.y; y++ {
+			c := hashCell{x, y}
+			if !old.contains(c) {
+				h.cells[c] = append(h.cells[c], sp)
+			}
+		}
+	}
+}
+
+func (cr cellRange) contains(c hashCell) bool {
+	return c.x >= cr.min.x && c.x <= cr.max.x &&
+		c.y >= cr.min.y && c.y <= cr.max.y
+}
+
+func (h *SpatialHash) addTo(sp *Space, cr cellRange) {
+	for x := cr.min.x; x <= cr.max.x; x++ {
+		for y := cr.min.y; y <= cr.max.y; y++ {
+			c := hashCell{x, y}
+			h.cells[c] = append(h.cells[c], sp)
+		}
+	}
+}
+
+func (h *SpatialHash) removeFrom(sp *Space, cr cellRange) {
+	for x := cr.min.x; x <= cr.max.x; x++ {
+		for y := cr.min.y; y <= cr.max.y; y++ {
+			h.removeFromCell(sp, hashCell{x, y})
+		}
+	}
+}
+
+func (h *SpatialHash) removeFromCell(sp *Space, c hashCell) {
+	sps := h.cells[c]
+	for i, s := range sps {
+		if s == sp {
+			sps[i] = sps[len(sps)-1]
+			sps[len(sps)-1] = nil
+			sps = sps[:len(sps)-1]
+			break
+		}
+	}
+	if len(sps) == 0 {
+		delete(h.cells, c)
+	} else {
+		h.cells[c] = sps
+	}
+}
+
+// SearchIntersect satisfies Backend
+func (h *SpatialHash) SearchIntersect(bb floatgeom.Rect3) []*Space {
+	results := []*Space{}
+	// When a search covers more cells than there are spaces, checking
+	// every space is faster than checking every cell
+	cr, ok := h.searchCells(bb)
+	if !ok {
+		for sp := range h.spaces {
+			if sp.Location.Intersects(bb) {
+				results = append(results, sp)
+			}
+		}
+		return results
+	}
+	var seen map[*Space]bool
+	if cr.size() > 1 {
+		seen = make(map[*Space]bool)
+	}
+	for x := cr.min.x; x <= cr.max.x; x++ {
+		for y := cr.min.y; y <= cr.max.y; y++ {
+			for _, sp := range h.cells[hashCell{x, y}] {
+				if seen != nil {
+					if seen[sp] {
+						continue
+					}
+					seen[sp] = true
+				}
+				if sp.Location.Intersects(bb) {
+					results = append(results, sp)
+				}
+			}
+		}
+	}
+	return results
+}
+
+// NearestNeighbor satisfies Backend
+func (h *SpatialHash) NearestNeighbor(p floatgeom.Point3) *Space {
+	return h.NearestNeighbors(1, p)[0]
+}
+
+// NearestNeighbors satisfies Backend. Cells are searched in rings outward
+// from p until no closer space could remain.
+func (h *SpatialHash) NearestNeighbors(k int, p floatgeom.Point3) []*Space {
+	if k <= 0 {
+		return []*Space{}
+	}
+	dists := make([]float64, k)
+	nearest := make([]*Space, k)
+	for i := range dists {
+		dists[i] = math.MaxFloat64
+	}
+	if len(h.spaces) == 0 {
+		return nearest
+	}
+	seen := make(map[*Space]bool)
+	center := h.cellOf(p.X(), p.Y())
+	step := math.Min(h.cellW, h.cellH)
+	visit := func(c hashCell) {
+		for _, sp := range h.cells[c] {
+			if seen[sp] {
+				continue
+			}
+			seen[sp] = true
+			dist := math.Sqrt(minDist(p, sp.Location))
+			dists, nearest = insertNearest(k, dists, nearest, dist, sp)
+		}
+	}
+	for ring := 0; len(seen) < len(h.spaces); ring++ {
+		// Anything in this ring or beyond is at least this far away
+		if float64(ring-1)*step > dists[k-1] {
+			break
+		}
+		if (2*ring+1)*(2*ring+1) > 4*len(h.cells) {
+			// The remaining spaces are far from p, relative to how many
+			// cells they fill, so check them directly
+			for sp := range h.spaces {
+				if !seen[sp] {
+					seen[sp] = true
+					dist := math.Sqrt(minDist(p, sp.Location))
+					dists, nearest = insertNearest(k, dists, nearest, dist, sp)
+				}
+			}
+			break
+		}
+		if ring == 0 {
+			visit(center)
+			continue
+		}
+		for x := center.x - ring; x <= center.x+ring; x++ {
+			visit(hashCell{x, center.y - ring})
+			visit(hashCell{x, center.y + ring})
+		}
+		for y := center.y - ring + 1; y <= center.y+ring-1; y++ {
+			visit(hashCell{center.x - ring, y})
+			visit(hashCell{center.x + ring, y})
+		}
+	}
+	return nearest
+}
+
+// Spaces satisfies Backend
+func (h *SpatialHash) Spaces() []*Space {
+	sps := make([]*Space, 0, len(h.spaces))
+	for sp := range h.spaces {
+		sps = append(sps, sp)
+	}
+	return sps
+}
+
+// Clear satisfies Backend
+func (h *SpatialHash) Clear() {
+	h.cells = make(map[hashCell][]*Space)
+	h.spaces = make(map[*Space]cellRange)
+}
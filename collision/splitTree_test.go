@@ -59,26 +59,51 @@ func BenchmarkSplitTreeHits(b *testing.B) {
 }
 
 func BenchmarkTreeHits(b *testing.B) {
-	t2, _ := NewTree(20, 40)
-	dynSpc := []*Space{}
+	for _, tb := range testBackends {
+		b.Run(tb.name, func(b *testing.B) {
+			t2, _ := NewBackendTree(tb.newLarge())
+			dynSpc := []*Space{}
 
-	for i := 0; i < staticElements; i++ {
-		t2.Add(randomSpace())
-	}
-	for i := 0; i < dynamicElements; i++ {
-		s := randomSpace()
-		dynSpc = append(dynSpc, s)
-		t2.Add(s)
+			for i := 0; i < staticElements; i++ {
+				t2.Add(randomSpace())
+			}
+			for i := 0; i < dynamicElements; i++ {
+				s := randomSpace()
+				dynSpc = append(dynSpc, s)
+				t2.Add(s)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s := randomSpace()
+				t2.Hits(s)
+				for _, d := range dynSpc {
+					t2.Remove(d)
+					p := floatgeom.Point3{xChange.Poll(), yChange.Poll(), 0}
+					d.Location.Min.Add(p)
+					d.Location.Max.Add(p)
+					t2.Add(d)
+				}
+			}
+		})
 	}
-	for i := 0; i < b.N; i++ {
-		s := randomSpace()
-		t2.Hits(s)
-		for _, d := range dynSpc {
-			t2.Remove(d)
-			p := floatgeom.Point3{xChange.Poll(), yChange.Poll(), 0}
-			d.Location.Min.Add(p)
-			d.Location.Max.Add(p)
-			t2.Add(d)
-		}
+}
+
+func BenchmarkTreeUpdateSpace(b *testing.B) {
+	for _, tb := range testBackends {
+		b.Run(tb.name, func(b *testing.B) {
+			t2, _ := NewBackendTree(tb.newLarge())
+			dynSpc := []*Space{}
+			for i := 0; i < staticElements; i++ {
+				s := randomSpace()
+				dynSpc = append(dynSpc, s)
+				t2.Add(s)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, d := range dynSpc {
+					t2.ShiftSpace(xChange.Poll(), yChange.Poll(), d)
+				}
+			}
+		})
 	}
 }
@@ -0,0 +1,181 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/physics"
+)
+
+// A SweepHit is where a space moving through a tree first touches
+// another space.
+type SweepHit struct {
+	// Time is the fraction of the motion, from 0 to 1, which the moving
+	// space completes before touching Space.
+	Time float64
+	// Normal is the unit normal of the side of Space which was touched,
+	// pointing away from Space.
+	Normal physics.Vector
+	// Space is the space which was touched.
+	Space *Space
+}
+
+// maxSweepSlides is the most times SweepMove will slide along
+// a surface in a single call.
+const maxSweepSlides = 4
+
+// Sweep returns the first space that sp would touch if it were moved by
+// delta, and whether there is any such space. Only spaces that pass the
+// given filters and the tree's Matrix are considered. Spaces which sp
+// already overlaps, and sp itself, are ignored. Unlike Hits, Sweep will
+// find spaces that sp would pass entirely through during its motion.
+// Spaces are swept as their rectangles, ignoring any shapes they have.
+func (t *Tree) Sweep(sp *Space, delta physics.Vector, fs ...Filter) (SweepHit, bool) {
+	if sp == nil {
+		return SweepHit{}, false
+	}
+	return t.sweep(sp, t.location(sp), delta, fs)
+}
+
+// location returns where sp is, without racing with updates to the tree.
+func (t *Tree) location(sp *Space) floatgeom.Rect3 {
+	t.RLock()
+	defer t.RUnlock()
+	return sp.Location
+}
+
+func (t *Tree) sweep(sp *Space, loc floatgeom.Rect3, delta physics.Vector, fs []Filter) (SweepHit, bool) {
+	moved := loc
+	moved.Min[0] += delta.X()
+	moved.Max[0] += delta.X()
+	moved.Min[1] += delta.Y()
+	moved.Max[1] += delta.Y()
+	t.RLock()
+	results := t.matrix.filter(sp.Label, t.Backend.SearchIntersect(loc.GreaterOf(moved)))
+	t.RUnlock()
+	for _, f := range fs {
+		if len(results) == 0 {
+			break
+		}
+		results = f(results)
+	}
+	t.RLock()
+	defer t.RUnlock()
+	best := SweepHit{Time: math.Inf(1)}
+	for _, other := range results {
+		if other == nil || other == sp {
+			continue
+		}
+		time, normal, ok := sweepRects(loc, delta, other.Location)
+		if ok && time < best.Time {
+			best = SweepHit{
+				Time:   time,
+				Normal: normal,
+				Space:  other,
+			}
+		}
+	}
+	return best, best.Space != nil
+}
+
+// sweepRects returns when, within a single step of delta, a rectangle moving
+// by delta will first touch another rectangle, and the normal of the side of
+// the other rectangle that it touches. Only the x and y dimensions are
+// considered.
+func sweepRects(r floatgeom.Rect3, delta physics.Vector, other floatgeom.Rect3) (float64, physics.Vector, bool) {
+	d := [2]float64{delta.X(), delta.Y()}
+	var entry, exit [2]float64
+	for i := 0; i < 2; i++ {
+		switch {
+		case d[i] > 0:
+			entry[i] = (other.Min[i] - r.Max[i]) / d[i]
+			exit[i] = (other.Max[i] - r.Min[i]) / d[i]
+		case d[i] < 0:
+			entry[i] = (other.Max[i] - r.Min[i]) / d[i]
+			exit[i] = (other.Min[i] - r.Max[i]) / d[i]
+		default:
+			// Not moving on this axis, so the rectangles must
+			// already overlap on it to ever touch.
+			if r.Max[i] <= other.Min[i] || r.Min[i] >= other.Max[i] {
+				return 0, physics.Vector{}, false
+			}
+			entry[i] = math.Inf(-1)
+			exit[i] = math.Inf(1)
+		}
+	}
+	axis := 0
+	if entry[1] > entry[0] {
+		axis = 1
+	}
+	tEntry := entry[axis]
+	tExit := math.Min(exit[0], exit[1])
+	if tEntry >= tExit || tEntry < 0 || tEntry > 1 {
+		return 0, physics.Vector{}, false
+	}
+	normal := [2]float64{}
+	if d[axis] > 0 {
+		normal[axis] = -1
+	} else {
+		normal[axis] = 1
+	}
+	return tEntry, physics.NewVector(normal[0], normal[1]), true
+}
+
+// SweepMove moves sp by delta through the tree, stopping it against any
+// spaces which pass the given filters. When sp is stopped, the remainder of
+// its motion is slid along the surface it touched. It returns how far sp
+// was actually moved, and the spaces it touched in order. See Sweep.
+func (t *Tree) SweepMove(sp *Space, delta physics.Vector, fs ...Filter) (physics.Vector, []SweepHit) {
+	if sp == nil {
+		return physics.NewVector(0, 0), nil
+	}
+	loc := t.location(sp)
+	start := loc
+	remaining := delta.Copy()
+	var hits []SweepHit
+	for i := 0; i < maxSweepSlides; i++ {
+		if remaining.X() == 0 && remaining.Y() == 0 {
+			break
+		}
+		hit, ok := t.sweep(sp, loc, remaining, fs)
+		if !ok {
+			loc = shiftRect(loc, remaining.X(), remaining.Y())
+			break
+		}
+		hits = append(hits, hit)
+		dx := remaining.X() * hit.Time
+		dy := remaining.Y() * hit.Time
+		// Snap exactly to the touched side, so that floating point error
+		// cannot leave sp overlapping what it touched.
+		other := hit.Space.Location
+		switch {
+		case hit.Normal.X() < 0:
+			dx = other.Min[0] - loc.Max[0]
+		case hit.Normal.X() > 0:
+			dx = other.Max[0] - loc.Min[0]
+		case hit.Normal.Y() < 0:
+			dy = other.Min[1] - loc.Max[1]
+		case hit.Normal.Y() > 0:
+			dy = other.Max[1] - loc.Min[1]
+		}
+		loc = shiftRect(loc, dx, dy)
+		// Slide whatever motion is left along the touched side
+		remaining = physics.NewVector(remaining.X()-dx, remaining.Y()-dy)
+		if hit.Normal.X() != 0 {
+			remaining = remaining.SetX(0)
+		} else {
+			remaining = remaining.SetY(0)
+		}
+	}
+	moved := physics.NewVector(loc.Min[0]-start.Min[0], loc.Min[1]-start.Min[1])
+	t.UpdateSpaceRect(loc, sp)
+	return moved, hits
+}
+
+func shiftRect(r floatgeom.Rect3, x, y float64) floatgeom.Rect3 {
+	r.Min[0] += x
+	r.Max[0] += x
+	r.Min[1] += y
+	r.Max[1] += y
+	return r
+}
@@ -0,0 +1,96 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/physics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSweep(t *testing.T) {
+	tree, _ := NewTree()
+	wall := NewLabeledSpace(50, 0, 10, 100, 1)
+	tree.Add(wall)
+
+	mover := NewUnassignedSpace(0, 10, 10, 10)
+	tree.Add(mover)
+
+	// Fast enough to pass through the wall in one step
+	hit, ok := tree.Sweep(mover, physics.NewVector(200, 0))
+	assert.True(t, ok)
+	assert.Equal(t, wall, hit.Space)
+	assert.InDelta(t, .2, hit.Time, .0001)
+	assert.Equal(t, -1.0, hit.Normal.X())
+	assert.Equal(t, 0.0, hit.Normal.Y())
+
+	// Moving away, or not far enough
+	_, ok = tree.Sweep(mover, physics.NewVector(-200, 0))
+	assert.False(t, ok)
+	_, ok = tree.Sweep(mover, physics.NewVector(20, 0))
+	assert.False(t, ok)
+	// Passing above the wall
+	_, ok = tree.Sweep(mover, physics.NewVector(200, -200))
+	assert.False(t, ok)
+	// Filtered out
+	_, ok = tree.Sweep(mover, physics.NewVector(200, 0), WithoutLabels(1))
+	assert.False(t, ok)
+	_, ok = tree.Sweep(nil, physics.NewVector(200, 0))
+	assert.False(t, ok)
+
+	// Touching, moving toward
+	toucher := NewUnassignedSpace(40, 10, 10, 10)
+	hit, ok = tree.Sweep(toucher, physics.NewVector(5, 0))
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, hit.Time)
+
+	// Already overlapping
+	_, ok = tree.Sweep(NewUnassignedSpace(52, 10, 2, 2), physics.NewVector(5, 0))
+	assert.False(t, ok)
+
+	// Earliest of several hits
+	near := NewLabeledSpace(30, 0, 5, 100, 2)
+	tree.Add(near)
+	hit, ok = tree.Sweep(mover, physics.NewVector(200, 0))
+	assert.True(t, ok)
+	assert.Equal(t, near, hit.Space)
+
+	// Vertical
+	floor := NewUnassignedSpace(-100, 200, 300, 10)
+	tree.Add(floor)
+	hit, ok = tree.Sweep(mover, physics.NewVector(0, 500))
+	assert.True(t, ok)
+	assert.Equal(t, floor, hit.Space)
+	assert.Equal(t, -1.0, hit.Normal.Y())
+}
+
+func TestSweepMove(t *testing.T) {
+	tree, _ := NewTree()
+	floor := NewUnassignedSpace(0, 100, 200, 10)
+	wall := NewUnassignedSpace(150, 0, 10, 100)
+	tree.Add(floor, wall)
+
+	mover := NewUnassignedSpace(0, 0, 10, 10)
+	tree.Add(mover)
+
+	// Falls onto the floor and slides along it into the wall
+	moved, hits := tree.SweepMove(mover, physics.NewVector(300, 300))
+	assert.Len(t, hits, 2)
+	assert.Equal(t, floor, hits[0].Space)
+	assert.Equal(t, wall, hits[1].Space)
+	assert.Equal(t, 140.0, moved.X())
+	assert.Equal(t, 90.0, moved.Y())
+	assert.Equal(t, 140.0, mover.X())
+	assert.Equal(t, 90.0, mover.Y())
+	assert.Empty(t, tree.Hits(mover))
+
+	// Unobstructed
+	moved, hits = tree.SweepMove(mover, physics.NewVector(-20, -20))
+	assert.Empty(t, hits)
+	assert.Equal(t, -20.0, moved.X())
+	assert.Equal(t, 120.0, mover.X())
+	assert.Equal(t, 70.0, mover.Y())
+
+	moved, hits = tree.SweepMove(nil, physics.NewVector(1, 1))
+	assert.Empty(t, hits)
+	assert.Equal(t, 0.0, moved.X())
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/oakmound/oak/alg/floatgeom"
 	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
 )
 
 var (
@@ -19,18 +20,22 @@ var (
 	DefaultMinChildren = 20
 )
 
-// A Tree provides a space for managing collisions between rectangles
+// A Tree provides a space for managing collisions between rectangles.
+// The spaces in a tree are stored in its Backend. Trees are safe for
+// concurrent use: queries hold a read lock on the tree, and modifications
+// hold its write lock.
 type Tree struct {
-	*Rtree
-	sync.Mutex
-	minChildren, maxChildren int
+	Backend
+	sync.RWMutex
+	matrix   *Matrix
+	watchers []*watcher
 }
 
 // NewTree returns a new collision Tree. The first argument will be used
 // as the minimum children per tree node. The second will be the maximum
 // children per tree node. Further arguments are ignored. If less than two
 // arguments are given, DefaultMinChildren and DefaultMaxChildren will be
-// used.
+// used. The returned tree is backed by an Rtree.
 func NewTree(children ...int) (*Tree, error) {
 	minChildren := DefaultMinChildren
 	maxChildren := DefaultMaxChildren
@@ -44,42 +49,55 @@ func NewTree(children ...int) (*Tree, error) {
 		return nil, errors.New("MaxChildren must exceed MinChildren")
 	}
 	return &Tree{
-		Rtree:       newTree(minChildren, maxChildren),
-		minChildren: minChildren,
-		maxChildren: maxChildren,
-		Mutex:       sync.Mutex{},
+		Backend: newTree(minChildren, maxChildren),
 	}, nil
 }
 
 // Clear resets a tree's contents to be empty
 func (t *Tree) Clear() {
-	t.Rtree = newTree(t.minChildren, t.maxChildren)
+	t.Lock()
+	t.Backend.Clear()
+	ws := t.watchers
+	t.Unlock()
+	notify(ws, []Change{{Kind: Cleared}})
 }
 
 // Add adds a set of spaces to the rtree
 func (t *Tree) Add(sps ...*Space) {
+	var changes []Change
 	t.Lock()
+	ws := t.watchers
 	for _, sp := range sps {
 		if sp != nil {
 			t.Insert(sp)
+			if ws != nil {
+				changes = append(changes, Change{Kind: Added, Space: sp, To: sp.Location})
+			}
 		}
 	}
 	t.Unlock()
+	notify(ws, changes)
 }
 
 // Remove removes spaces from the rtree and
 // returns the number of spaces removed.
 func (t *Tree) Remove(sps ...*Space) int {
 	removed := 0
+	var changes []Change
 	t.Lock()
+	ws := t.watchers
 	for _, sp := range sps {
 		if sp != nil {
 			if t.Delete(sp) {
 				removed++
+				if ws != nil {
+					changes = append(changes, Change{Kind: Removed, Space: sp, From: sp.Location})
+				}
 			}
 		}
 	}
 	t.Unlock()
+	notify(ws, changes)
 	return removed
 }
 
@@ -100,13 +118,7 @@ func (t *Tree) UpdateSpace(x, y, w, h float64, s *Space) error {
 	if s == nil {
 		return oakerr.NilInput{InputName: "s"}
 	}
-	loc := NewRect(x, y, w, h)
-	t.Lock()
-	t.Delete(s)
-	s.Location = loc
-	t.Insert(s)
-	t.Unlock()
-	return nil
+	return t.UpdateSpaceRect(NewRect(x, y, w, h), s)
 }
 
 // UpdateSpaceRect acts as UpdateSpace, but takes in a rectangle instead
@@ -116,10 +128,13 @@ func (t *Tree) UpdateSpaceRect(rect floatgeom.Rect3, s *Space) error {
 		return oakerr.NilInput{InputName: "s"}
 	}
 	t.Lock()
-	t.Delete(s)
-	s.Location = rect
-	t.Insert(s)
+	from := s.Location
+	t.Update(s, rect)
+	ws := t.watchers
 	t.Unlock()
+	if ws != nil {
+		notify(ws, []Change{{Kind: Moved, Space: s, From: from, To: rect}})
+	}
 	return nil
 }
 
@@ -136,11 +151,15 @@ func (t *Tree) ShiftSpace(x, y float64, s *Space) error {
 // Hits returns the set of spaces which are colliding
 // with the passed in space. All spaces collide with
 // themselves, if they exist in the tree, but self-collision
-// will not be reported by Hits.
+// will not be reported by Hits. Spaces with shapes only
+// collide if their shapes overlap, and if the tree has a
+// Matrix, only spaces with labels that can collide with
+// the passed in space's label are returned.
 func (t *Tree) Hits(sp *Space) []*Space {
+	t.RLock()
 	// Eventually we'll expose SearchIntersect for use cases where you
 	// want to see if you intersect yourself
-	results := t.SearchIntersect(sp.Bounds())
+	results := t.Backend.SearchIntersect(sp.Bounds())
 	hitSelf := -1
 	i := 0
 	for i < len(results) {
@@ -151,6 +170,8 @@ func (t *Tree) Hits(sp *Space) []*Space {
 			i++
 		}
 	}
+	results = narrowHits(sp, t.matrix.filter(sp.Label, results))
+	t.RUnlock()
 	out := make([]*Space, len(results))
 	for i, v := range results {
 		if v == sp {
@@ -170,10 +191,12 @@ func (t *Tree) Hits(sp *Space) []*Space {
 // space that is passed into it, if that space has a label in the set of
 // accepted labels.
 func (t *Tree) HitLabel(sp *Space, labels ...Label) *Space {
-	results := t.SearchIntersect(sp.Bounds())
+	t.RLock()
+	defer t.RUnlock()
+	results := t.matrix.filter(sp.Label, t.Backend.SearchIntersect(sp.Bounds()))
 	for _, v := range results {
 		for _, label := range labels {
-			if v != sp && v.Label == label {
+			if v != sp && v.Label == label && narrowHit(sp, v) {
 				return v
 			}
 		}
@@ -182,9 +205,12 @@ func (t *Tree) HitLabel(sp *Space, labels ...Label) *Space {
 }
 
 // Hit is an experimental new syntax that probably has performance hits
-// relative to Hits/HitLabel, see filters.go
+// relative to Hits/HitLabel, see filters.go. Filters are called without
+// the tree locked, so they may query or modify the tree.
 func (t *Tree) Hit(sp *Space, fs ...Filter) []*Space {
-	results := t.SearchIntersect(sp.Bounds())
+	t.RLock()
+	results := narrowHits(sp, t.matrix.filter(sp.Label, t.Backend.SearchIntersect(sp.Bounds())))
+	t.RUnlock()
 	for _, f := range fs {
 		if len(results) == 0 {
 			return results
@@ -193,3 +219,64 @@ func (t *Tree) Hit(sp *Space, fs ...Filter) []*Space {
 	}
 	return results
 }
+
+// A Contact is a space that was hit, along with the minimum translation
+// vector that would move the hitting space out of it.
+type Contact struct {
+	Space *Space
+	MTV   physics.Vector
+}
+
+// Contacts acts like Hit, but also returns the minimum translation vector
+// for each space hit. See Space.MTV.
+func (t *Tree) Contacts(sp *Space, fs ...Filter) []Contact {
+	results := t.Hit(sp, fs...)
+	contacts := make([]Contact, 0, len(results))
+	t.RLock()
+	defer t.RUnlock()
+	for _, v := range results {
+		if v == nil || v == sp {
+			continue
+		}
+		mtv, ok := sp.MTV(v)
+		if !ok {
+			continue
+		}
+		contacts = append(contacts, Contact{
+			Space: v,
+			MTV:   mtv,
+		})
+	}
+	return contacts
+}
+
+// SearchIntersect returns all spaces in the tree that intersect bb.
+func (t *Tree) SearchIntersect(bb floatgeom.Rect3) []*Space {
+	t.RLock()
+	defer t.RUnlock()
+	return t.Backend.SearchIntersect(bb)
+}
+
+// NearestNeighbor returns the closest space in the tree to p, or nil if
+// the tree is empty.
+func (t *Tree) NearestNeighbor(p floatgeom.Point3) *Space {
+	t.RLock()
+	defer t.RUnlock()
+	return t.Backend.NearestNeighbor(p)
+}
+
+// NearestNeighbors returns the k closest spaces in the tree to p in order
+// of increasing distance. If there are fewer than k spaces, the remainder
+// of the returned slice is nil.
+func (t *Tree) NearestNeighbors(k int, p floatgeom.Point3) []*Space {
+	t.RLock()
+	defer t.RUnlock()
+	return t.Backend.NearestNeighbors(k, p)
+}
+
+// Spaces returns every space in the tree.
+func (t *Tree) Spaces() []*Space {
+	t.RLock()
+	defer t.RUnlock()
+	return t.Backend.Spaces()
+}
@@ -0,0 +1,4 @@
+// Package visibility computes what can be seen from points among the
+// spaces of a collision tree, for line of sight checks, fog of war and
+// lighting.
+package visibility
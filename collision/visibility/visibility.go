@@ -0,0 +1,224 @@
+package visibility
+
+import (
+	"math"
+	"sort"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/render"
+)
+
+var (
+	// CircleSides is how many sides the polygon approximating the edge of
+	// a Region's radius has.
+	CircleSides = 64
+)
+
+const (
+	// sweepOffset is how far to either side of each corner, in radians,
+	// rays are cast to see past the corner
+	sweepOffset = 1e-7
+	// epsilon is used to compare points and distances
+	epsilon = 1e-9
+)
+
+// A Region is the area visible from an origin within some radius.
+type Region struct {
+	Origin floatgeom.Point2
+	Radius float64
+	// Points are the points of the visibility polygon in order of
+	// increasing angle around Origin.
+	Points []floatgeom.Point2
+}
+
+type segment struct {
+	a, b floatgeom.Point2
+}
+
+// NewRegion computes the area visible from origin within radius, where the
+// rectangles of spaces in tree with one of the given labels block sight.
+// If no labels are given, all spaces block sight. Spaces which contain
+// origin do not block sight. If tree is nil, collision.DefTree is used.
+//
+// The edge of the region's radius is approximated with a polygon of
+// CircleSides sides. Within it, the region is exact.
+func NewRegion(tree *collision.Tree, origin floatgeom.Point2, radius float64, occluders ...collision.Label) Region {
+	r := Region{
+		Origin: origin,
+		Radius: radius,
+	}
+	if radius <= 0 {
+		return r
+	}
+	segs := occludingSegments(tree, origin, radius, occluders)
+	sides := CircleSides
+	if sides < 3 {
+		sides = 3
+	}
+	for i := 0; i < sides; i++ {
+		a := 2 * math.Pi * float64(i) / float64(sides)
+		b := 2 * math.Pi * float64(i+1) / float64(sides)
+		segs = append(segs, segment{
+			origin.Add(floatgeom.RadianPoint(a).MulConst(radius)),
+			origin.Add(floatgeom.RadianPoint(b).MulConst(radius)),
+		})
+	}
+
+	// The closest segment along any ray only changes at segment
+	// endpoints and where segments cross, so those are the only
+	// angles which need to be cast toward.
+	var angles []float64
+	addAngle := func(p floatgeom.Point2) {
+		d := p.Sub(origin)
+		if d.Magnitude() > radius+epsilon {
+			return
+		}
+		a := math.Atan2(d.Y(), d.X())
+		angles = append(angles, a-sweepOffset, a, a+sweepOffset)
+	}
+	for i, s := range segs {
+		addAngle(s.a)
+		addAngle(s.b)
+		for _, s2 := range segs[i+1:] {
+			if p, ok := intersect(s, s2); ok {
+				addAngle(p)
+			}
+		}
+	}
+	sort.Float64s(angles)
+
+	for _, a := range angles {
+		dir := floatgeom.RadianPoint(a)
+		dist := radius
+		for _, s := range segs {
+			if d, ok := castSegment(origin, dir, s); ok && d < dist {
+				dist = d
+			}
+		}
+		p := origin.Add(dir.MulConst(dist))
+		if n := len(r.Points); n > 0 && r.Points[n-1].Distance(p) < epsilon*radius {
+			continue
+		}
+		r.Points = append(r.Points, p)
+	}
+	if n := len(r.Points); n > 1 && r.Points[0].Distance(r.Points[n-1]) < epsilon*radius {
+		r.Points = r.Points[:n-1]
+	}
+	return r
+}
+
+func occludingSegments(tree *collision.Tree, origin floatgeom.Point2, radius float64, occluders []collision.Label) []segment {
+	if tree == nil {
+		tree = collision.DefTree
+	}
+	bounds := floatgeom.NewRect3(
+		origin.X()-radius, origin.Y()-radius, math.Inf(-1),
+		origin.X()+radius, origin.Y()+radius, math.Inf(1),
+	)
+	var segs []segment
+	for _, sp := range tree.SearchIntersect(bounds) {
+		if sp == nil || !occludes(sp, occluders) || contains(sp, origin) {
+			continue
+		}
+		min := floatgeom.Point2{sp.Location.Min.X(), sp.Location.Min.Y()}
+		max := floatgeom.Point2{sp.Location.Max.X(), sp.Location.Max.Y()}
+		corners := [4]floatgeom.Point2{
+			min,
+			{max.X(), min.Y()},
+			max,
+			{min.X(), max.Y()},
+		}
+		for i := range corners {
+			segs = append(segs, segment{corners[i], corners[(i+1)%4]})
+		}
+	}
+	return segs
+}
+
+func occludes(sp *collision.Space, occluders []collision.Label) bool {
+	return len(occluders) == 0 || sp.HasLabel(occluders...)
+}
+
+func contains(sp *collision.Space, p floatgeom.Point2) bool {
+	return p.X() > sp.Location.Min.X() && p.X() < sp.Location.Max.X() &&
+		p.Y() > sp.Location.Min.Y() && p.Y() < sp.Location.Max.Y()
+}
+
+func cross(a, b floatgeom.Point2) float64 {
+	return a.X()*b.Y() - a.Y()*b.X()
+}
+
+// castSegment returns how far along the ray from origin in the unit
+// direction dir the ray crosses s.
+func castSegment(origin, dir floatgeom.Point2, s segment) (float64, bool) {
+	edge := s.b.Sub(s.a)
+	denom := cross(dir, edge)
+	if denom == 0 {
+		return 0, false
+	}
+	diff := s.a.Sub(origin)
+	t := cross(diff, edge) / denom
+	u := cross(diff, dir) / denom
+	if t < 0 || u < -epsilon || u > 1+epsilon {
+		return 0, false
+	}
+	return t, true
+}
+
+// intersect returns where two segments cross, if they do.
+func intersect(s1, s2 segment) (floatgeom.Point2, bool) {
+	d1 := s1.b.Sub(s1.a)
+	d2 := s2.b.Sub(s2.a)
+	denom := cross(d1, d2)
+	if denom == 0 {
+		return floatgeom.Point2{}, false
+	}
+	diff := s2.a.Sub(s1.a)
+	t := cross(diff, d2) / denom
+	u := cross(diff, d1) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return floatgeom.Point2{}, false
+	}
+	return s1.a.Add(d1.MulConst(t)), true
+}
+
+// Contains returns whether p is visible within the region.
+func (r Region) Contains(p floatgeom.Point2) bool {
+	in := false
+	for i, a := range r.Points {
+		b := r.Points[(i+1)%len(r.Points)]
+		if (a.Y() > p.Y()) != (b.Y() > p.Y()) &&
+			p.X() < (b.X()-a.X())*(p.Y()-a.Y())/(b.Y()-a.Y())+a.X() {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Polygon converts the region into a render.Polygon, to be filled in for
+// fog of war or lighting.
+func (r Region) Polygon() (*render.Polygon, error) {
+	return render.NewPolygon(r.Points...)
+}
+
+// LineOfSight returns whether nothing blocks sight from one point to
+// another, where the rectangles of spaces in tree with one of the given
+// labels block sight. If no labels are given, all spaces block sight.
+// Spaces which contain from do not block sight, nor do spaces which are
+// only touched at to. If tree is nil, collision.DefTree is used.
+func LineOfSight(tree *collision.Tree, from, to floatgeom.Point2, occluders ...collision.Label) bool {
+	if tree == nil {
+		tree = collision.DefTree
+	}
+	dist := from.Distance(to)
+	for _, p := range tree.RayHits(from, to.Sub(from), dist) {
+		if !occludes(p.Zone, occluders) || contains(p.Zone, from) {
+			continue
+		}
+		if p.Distance < dist-epsilon {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package visibility
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	wall collision.Label = iota + 1
+	glass
+)
+
+func testTree(t *testing.T) *collision.Tree {
+	tree, err := collision.NewTree()
+	assert.Nil(t, err)
+	tree.Add(collision.NewLabeledSpace(10, -5, 2, 10, wall))
+	tree.Add(collision.NewLabeledSpace(-12, -5, 2, 10, glass))
+	return tree
+}
+
+func TestNewRegion(t *testing.T) {
+	tree := testTree(t)
+
+	r := NewRegion(tree, floatgeom.Point2{0, 0}, 50, wall)
+	assert.True(t, len(r.Points) >= CircleSides)
+	assert.True(t, r.Contains(floatgeom.Point2{5, 0}))
+	assert.True(t, r.Contains(floatgeom.Point2{0, 30}))
+	assert.False(t, r.Contains(floatgeom.Point2{20, 0}))
+	assert.False(t, r.Contains(floatgeom.Point2{20, 3}))
+	// Glass does not occlude
+	assert.True(t, r.Contains(floatgeom.Point2{-20, 0}))
+	// Outside of the radius
+	assert.False(t, r.Contains(floatgeom.Point2{0, 60}))
+	// Past the corner of the wall
+	assert.True(t, r.Contains(floatgeom.Point2{20, 15}))
+
+	// The shadow behind the wall is exactly bounded by its corners
+	for _, p := range r.Points {
+		if p.X() > 12 && p.X() < 40 {
+			assert.False(t, p.Y() > -p.X()/2+1e-6 && p.Y() < p.X()/2-1e-6, p)
+		}
+	}
+
+	r = NewRegion(tree, floatgeom.Point2{0, 0}, 50)
+	assert.False(t, r.Contains(floatgeom.Point2{-20, 0}))
+
+	r = NewRegion(tree, floatgeom.Point2{0, 0}, 0)
+	assert.Empty(t, r.Points)
+	assert.False(t, r.Contains(floatgeom.Point2{0, 0}))
+
+	// Spaces around the origin do not occlude
+	r = NewRegion(tree, floatgeom.Point2{11, 0}, 50, wall)
+	assert.True(t, r.Contains(floatgeom.Point2{30, 0}))
+
+	poly, err := r.Polygon()
+	assert.Nil(t, err)
+	assert.NotNil(t, poly)
+}
+
+func TestLineOfSight(t *testing.T) {
+	tree := testTree(t)
+	assert.False(t, LineOfSight(tree, floatgeom.Point2{0, 0}, floatgeom.Point2{20, 0}, wall))
+	assert.True(t, LineOfSight(tree, floatgeom.Point2{0, 0}, floatgeom.Point2{-20, 0}, wall))
+	assert.False(t, LineOfSight(tree, floatgeom.Point2{0, 0}, floatgeom.Point2{-20, 0}))
+	assert.True(t, LineOfSight(tree, floatgeom.Point2{0, 0}, floatgeom.Point2{10, 0}, wall))
+	assert.True(t, LineOfSight(tree, floatgeom.Point2{0, 0}, floatgeom.Point2{20, 20}, wall))
+	assert.True(t, LineOfSight(tree, floatgeom.Point2{11, 0}, floatgeom.Point2{20, 0}, wall))
+	assert.True(t, LineOfSight(tree, floatgeom.Point2{0, 0}, floatgeom.Point2{0, 0}, wall))
+}
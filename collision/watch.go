@@ -0,0 +1,69 @@
+package collision
+
+import "github.com/oakmound/oak/alg/floatgeom"
+
+// A ChangeKind is a way a tree's contents can change.
+type ChangeKind int
+
+// ChangeKind values
+const (
+	// Added changes report a space being added to a tree.
+	Added ChangeKind = iota
+	// Moved changes report a space in a tree changing location.
+	Moved
+	// Removed changes report a space being removed from a tree.
+	Removed
+	// Cleared changes report every space being removed from a tree at
+	// once. They have no Space.
+	Cleared
+)
+
+// A Change describes a space being added to, moved within or removed from
+// a tree.
+type Change struct {
+	Kind  ChangeKind
+	Space *Space
+	// From is where the space was before being moved or removed.
+	From floatgeom.Rect3
+	// To is where the space is after being added or moved.
+	To floatgeom.Rect3
+}
+
+type watcher struct {
+	f func(Change)
+}
+
+// Watch calls f with each change made to the tree through its methods,
+// until the returned function is called. Changes are reported after the
+// tree is unlocked, from the goroutine which made them, so f may query or
+// modify the tree.
+func (t *Tree) Watch(f func(Change)) (stop func()) {
+	w := &watcher{f: f}
+	t.Lock()
+	t.watchers = append(t.watchers[:len(t.watchers):len(t.watchers)], w)
+	t.Unlock()
+	return func() {
+		t.Lock()
+		// Watchers are copied rather than modified in place, so changes
+		// can be reported from a slice taken while the tree was locked
+		ws := make([]*watcher, 0, len(t.watchers))
+		for _, w2 := range t.watchers {
+			if w2 != w {
+				ws = append(ws, w2)
+			}
+		}
+		if len(ws) == 0 {
+			ws = nil
+		}
+		t.watchers = ws
+		t.Unlock()
+	}
+}
+
+func notify(ws []*watcher, changes []Change) {
+	for _, c := range changes {
+		for _, w := range ws {
+			w.f(c)
+		}
+	}
+}
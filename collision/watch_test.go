@@ -0,0 +1,38 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	tree, _ := NewTree()
+	var changes []Change
+	stop := tree.Watch(func(c Change) {
+		changes = append(changes, c)
+	})
+	s1 := NewUnassignedSpace(0, 0, 10, 10)
+	s2 := NewUnassignedSpace(20, 20, 10, 10)
+	tree.Add(s1, nil, s2)
+	tree.UpdateSpace(5, 5, 10, 10, s1)
+	tree.Remove(s2, s2)
+	tree.UpdateSpaces(SpaceUpdate{Space: s1, Location: NewRect(1, 1, 1, 1)})
+	tree.Clear()
+	tree.Load(s2)
+	assert.Equal(t, []Change{
+		{Kind: Added, Space: s1, To: NewRect(0, 0, 10, 10)},
+		{Kind: Added, Space: s2, To: NewRect(20, 20, 10, 10)},
+		{Kind: Moved, Space: s1, From: NewRect(0, 0, 10, 10), To: NewRect(5, 5, 10, 10)},
+		{Kind: Removed, Space: s2, From: NewRect(20, 20, 10, 10)},
+		{Kind: Moved, Space: s1, From: NewRect(5, 5, 10, 10), To: NewRect(1, 1, 1, 1)},
+		{Kind: Cleared},
+		{Kind: Cleared},
+		{Kind: Added, Space: s2, To: NewRect(20, 20, 10, 10)},
+	}, changes)
+
+	stop()
+	changes = nil
+	tree.Add(s1)
+	assert.Empty(t, changes)
+}
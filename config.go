@@ -30,6 +30,7 @@ var (
 		false,
 		false,
 		false,
+		false,
 	}
 )
 
@@ -46,6 +47,9 @@ type Config struct {
 	BatchLoad           bool   `json:"batchLoad"`
 	GestureSupport      bool   `json:"gestureSupport"`
 	LoadBuiltinCommands bool   `json:"loadBuiltinCommands"`
+	// FrameStepping stops the logic and draw loops from ticking on their own,
+	// instead only advancing frames when Step is called.
+	FrameStepping bool `json:"frameStepping"`
 }
 
 // Assets is a json type storing paths to different asset folders
@@ -185,6 +189,7 @@ func initConf() {
 	conf.BatchLoad = SetupConfig.BatchLoad
 	conf.GestureSupport = SetupConfig.GestureSupport
 	conf.LoadBuiltinCommands = SetupConfig.LoadBuiltinCommands
+	conf.FrameStepping = SetupConfig.FrameStepping
 
 	dlog.Error(conf)
 }
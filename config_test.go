@@ -27,6 +27,8 @@ func TestDefaultConfig(t *testing.T) {
 		true,
 		true,
 		true,
+		// Frame stepping is left off so later tests can run the engine
+		false,
 	}
 	initConf()
 	assert.Equal(t, SetupConfig, conf)
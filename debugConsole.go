@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -51,6 +53,7 @@ func debugConsole(resetCh, skipScene chan bool, input io.Reader) {
 		dlog.ErrorCheck(AddCommand("skip", skipCommands(skipScene)))
 		dlog.ErrorCheck(AddCommand("print", printCommands))
 		dlog.ErrorCheck(AddCommand("mouse", mouseCommands))
+		dlog.ErrorCheck(AddCommand("events", eventCommands))
 		dlog.ErrorCheck(AddCommand("move", moveWindow))
 		dlog.ErrorCheck(AddCommand("fullscreen", fullScreen))
 		dlog.ErrorCheck(AddCommand("quit", func([]string) { Quit() }))
@@ -86,8 +89,7 @@ func parseTokenAsInt(tokenString []string, arrIndex int, defaultVal int) int {
 	return defaultVal
 }
 
-func mouseDetails(nothing int, mevent interface{}) int {
-	me := mevent.(mouse.Event)
+func mouseDetails(nothing int, me mouse.Event) int {
 	x := int(me.X()) + ViewPos.X
 	y := int(me.Y()) + ViewPos.Y
 	loc := collision.NewUnassignedSpace(float64(x), float64(y), 16, 16)
@@ -167,12 +169,60 @@ func printCommands(tokenString []string) {
 func mouseCommands(tokenString []string) {
 	switch tokenString[0] {
 	case "details":
-		event.GlobalBind(mouseDetails, "MouseRelease")
+		dlog.ErrorCheck(event.GlobalBindTyped(mouseDetails, mouse.Release))
 	default:
 		fmt.Println("Bad Mouse Input")
 	}
 }
 
+// eventCommands prints what is bound on the logic handler, and how
+// long bindables have run for while profiling is on.
+func eventCommands(tokenString []string) {
+	bus, ok := logicHandler.(*event.Bus)
+	if !ok {
+		fmt.Println("Logic handler does not support introspection")
+		return
+	}
+	if len(tokenString) > 0 {
+		switch tokenString[0] {
+		case "profile":
+			bus.SetProfiling(true)
+		case "stop":
+			bus.SetProfiling(false)
+		case "reset":
+			bus.ResetProfile()
+		default:
+			fmt.Println("Bad Events Input")
+		}
+		return
+	}
+	printSnapshot(os.Stdout, bus.Snapshot())
+}
+
+func printSnapshot(w io.Writer, s event.Snapshot) {
+	fmt.Fprintln(w, "Event", "Caller", "Priority", "Bindings")
+	for _, b := range s.Bindings {
+		fmt.Fprintln(w, b.Name, b.CallerID, b.Priority, b.Count)
+	}
+	if len(s.Stats) == 0 {
+		return
+	}
+	// Events which have taken the longest are printed first
+	names := make([]string, 0, len(s.Stats))
+	for name := range s.Stats {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return s.Stats[names[i]].TotalTime > s.Stats[names[j]].TotalTime
+	})
+	fmt.Fprintln(w, "Event", "Triggers", "Calls", "Total", "Max", "Errors", "UnbindEvents", "UnbindSingles")
+	for _, name := range names {
+		st := s.Stats[name]
+		fmt.Fprintln(w, name, st.Triggers, st.Calls, st.TotalTime, st.MaxTime,
+			st.Errors, st.UnbindEvents, st.UnbindSingles)
+	}
+}
+
 func moveWindow(in []string) {
 	if len(in) < 4 {
 		dlog.Error("Insufficient integer arguments for moving window")
@@ -2,7 +2,9 @@ package oak
 
 import (
 	"bytes"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/oakmound/oak/collision"
 	"github.com/oakmound/oak/event"
@@ -46,6 +48,11 @@ func TestDebugConsole(t *testing.T) {
 			"print 1\n" +
 			"mouse nothing\n" +
 			"mouse details\n" +
+			"events profile\n" +
+			"events\n" +
+			"events reset\n" +
+			"events stop\n" +
+			"events nothing\n" +
 			"garbage input\n" +
 			"\n" +
 			"skip scene\n")
@@ -57,6 +64,27 @@ func TestDebugConsole(t *testing.T) {
 	<-sCh
 }
 
+func TestPrintSnapshot(t *testing.T) {
+	buff := new(bytes.Buffer)
+	printSnapshot(buff, event.Snapshot{
+		Bindings: []event.BindingCount{
+			{
+				BindingOption: event.BindingOption{
+					Event: event.Event{Name: event.Enter, CallerID: 1},
+				},
+				Count: 2,
+			},
+		},
+		Stats: map[string]event.EventStats{
+			"Fast": {Triggers: 1, TotalTime: time.Millisecond},
+			"Slow": {Triggers: 1, TotalTime: time.Second},
+		},
+	})
+	out := buff.String()
+	assert.Contains(t, out, event.Enter+" 1 0 2")
+	assert.True(t, strings.Index(out, "Slow") < strings.Index(out, "Fast"))
+}
+
 func TestMouseDetails(t *testing.T) {
 	mouseDetails(0, mouse.NewZeroEvent(0, 0))
 	s := collision.NewUnassignedSpace(-1, -1, 2, 2)
@@ -35,7 +35,10 @@ func drawLoop() {
 	drawLoopPublish(tx)
 
 	DrawTicker = timing.NewDynamicTicker()
-	DrawTicker.SetTick(timing.FPSToDuration(DrawFrameRate))
+	// When frame stepping, frames are only drawn through drawStepCh
+	if !conf.FrameStepping {
+		DrawTicker.SetTick(timing.FPSToDuration(DrawFrameRate))
+	}
 
 	dlog.Verb("Draw Loop Start")
 	for {
@@ -48,34 +51,40 @@ func drawLoop() {
 			<-drawCh
 			dlog.Verb("Starting loading")
 			for {
-				<-DrawTicker.C
-				draw.Draw(winBuffer.RGBA(), winBuffer.Bounds(), Background, zeroPoint, draw.Src)
-				if LoadingR != nil {
-					LoadingR.Draw(winBuffer.RGBA())
-				}
-				drawLoopPublish(tx)
-
 				select {
+				case <-DrawTicker.C:
+					draw.Draw(winBuffer.RGBA(), winBuffer.Bounds(), Background, zeroPoint, draw.Src)
+					if LoadingR != nil {
+						LoadingR.Draw(winBuffer.RGBA())
+					}
+					drawLoopPublish(tx)
 				case <-drawCh:
 					break drawSelect
 				case viewPoint := <-viewportCh:
 					dlog.Verb("Got something from viewport channel (waiting on draw)")
 					updateScreen(viewPoint[0], viewPoint[1])
-				default:
 				}
 			}
 		case viewPoint := <-viewportCh:
 			dlog.Verb("Got something from viewport channel")
 			updateScreen(viewPoint[0], viewPoint[1])
 		case <-DrawTicker.C:
-			draw.Draw(winBuffer.RGBA(), winBuffer.Bounds(), Background, zeroPoint, draw.Src)
-			render.PreDraw()
-			render.GlobalDrawStack.Draw(winBuffer.RGBA(), ViewPos, ScreenWidth, ScreenHeight)
-			drawLoopPublish(tx)
+			drawFrame(tx)
+		case <-drawStepCh:
+			drawFrame(tx)
+			drawStepCh <- true
 		}
 	}
 }
 
+func drawFrame(tx screen.Texture) {
+	draw.Draw(winBuffer.RGBA(), winBuffer.Bounds(), Background, zeroPoint, draw.Src)
+	render.PreDraw()
+	drawSuspendedScenes(winBuffer.RGBA())
+	render.GlobalDrawStack.Draw(winBuffer.RGBA(), ViewPos, ScreenWidth, ScreenHeight)
+	drawLoopPublish(tx)
+}
+
 var (
 	drawLoopPublishDef = func(tx screen.Texture) {
 		tx.Upload(zeroPoint, winBuffer, winBuffer.Bounds())
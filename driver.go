@@ -1,6 +1,7 @@
 package oak
 
 import (
+	"github.com/oakmound/oak/headless"
 	"github.com/oakmound/shiny/driver"
 	"github.com/oakmound/shiny/screen"
 )
@@ -20,4 +21,8 @@ var (
 	// a C compiler, but still compile without using this if you
 	// don't"
 	// GLDriver = gldriver.Main
+
+	// HeadlessDriver runs oak without a window, drawing to and reading events
+	// from in-memory buffers. See the headless package.
+	HeadlessDriver = headless.Main
 )
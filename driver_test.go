@@ -0,0 +1,40 @@
+package oak
+
+import (
+	"os"
+	"testing"
+
+	"github.com/oakmound/oak/headless"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/size"
+)
+
+func TestMain(m *testing.M) {
+	// Tests run without a display
+	InitDriver = HeadlessDriver
+	os.Exit(m.Run())
+}
+
+func TestHeadlessDriver(t *testing.T) {
+	resetOak()
+	testinit()
+	w := headless.DefaultScreen.Window()
+	assert.NotNil(t, w)
+
+	w.Send(key.Event{Code: key.CodeA, Direction: key.DirPress})
+	sleep()
+	assert.True(t, IsDown("A"))
+	w.Send(key.Event{Code: key.CodeA, Direction: key.DirRelease})
+	sleep()
+	assert.False(t, IsDown("A"))
+
+	publishes := w.Publishes()
+	sleep()
+	assert.True(t, w.Publishes() > publishes)
+
+	w.Send(size.Event{WidthPx: ScreenWidth * 2, HeightPx: ScreenHeight * 2})
+	sleep()
+	assert.Equal(t, ScreenWidth*2, w.Published().Bounds().Dx())
+	assert.Equal(t, ScreenHeight*2, windowRect.Max.Y)
+}
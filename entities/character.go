@@ -0,0 +1,357 @@
+package entities
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/alg"
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+)
+
+const (
+	// maxCharacterSlides is the most times a character's motion can be
+	// redirected along what it touches in a single move, and the most
+	// times a character is pushed out of what it overlaps after each step
+	maxCharacterSlides = 8
+	// characterEpsilon is the distance below which motion is ignored
+	characterEpsilon = 1e-6
+	// stepSink is how far characters sink into steps they walk up
+	stepSink = .01
+)
+
+// A Character moves a collision space through a tree as the character of a
+// platformer moves. It falls, jumps, walks up slopes and steps, lands on
+// one-way platforms and slides along walls, and tracks whether it is on
+// the ground, against a wall or against a ceiling.
+//
+// The character's position is kept in its Vector, which is set to the top
+// left of its space after each move.
+type Character struct {
+	physics.Vector
+	Space *collision.Space
+	Tree  *collision.Tree
+	// Delta is how far the character moves each frame. Its y value is
+	// changed by gravity, jumping, and landing on and hitting things, but
+	// its x value is only changed by hitting walls.
+	Delta physics.Vector
+	// Solid are the labels of spaces the character cannot pass through.
+	// If it is empty, every space is solid.
+	Solid []collision.Label
+	// OneWay are the labels of platforms which the character can jump up
+	// through and land on. One-way platforms are treated as rectangles,
+	// and are never solid otherwise.
+	OneWay []collision.Label
+	// Gravity is added to the y value of Delta each frame.
+	Gravity float64
+	// JumpSpeed is the upward speed characters jump with.
+	JumpSpeed float64
+	// MaxSlope is the steepest slope, in degrees, which the character can
+	// stand on and walk up. Steeper slopes are walls. Ceilings are spaces
+	// the character touches from below which are at most as steep.
+	MaxSlope float64
+	// StepHeight is the tallest step the character walks up without
+	// jumping, and how far it will follow the ground down to stay on it.
+	StepHeight float64
+	// CoyoteFrames is how many frames after walking off of the ground
+	// the character can still jump.
+	CoyoteFrames int
+	// JumpBufferFrames is how many frames before landing the character
+	// remembers being asked to jump.
+	JumpBufferFrames int
+
+	touch      characterTouch
+	coyote     int
+	jumpBuffer int
+}
+
+// characterTouch is what a character touched during a move.
+type characterTouch struct {
+	grounded    bool
+	onCeiling   bool
+	wall        int
+	floor       *collision.Space
+	floorNormal floatgeom.Point2
+}
+
+// NewCharacter returns a character which moves the given space through
+// the given tree, or through collision.DefTree if tree is nil, and keeps v
+// at the position of the space. If any labels are given, only spaces with
+// those labels are solid. The character can walk up slopes of up to 45
+// degrees, and has no gravity. It returns an error if sp is nil.
+func NewCharacter(sp *collision.Space, v physics.Vector, tree *collision.Tree, solid ...collision.Label) (*Character, error) {
+	if sp == nil {
+		return nil, oakerr.NilInput{InputName: "sp"}
+	}
+	if tree == nil {
+		tree = collision.DefTree
+	}
+	v.SetPos(sp.X(), sp.Y())
+	return &Character{
+		Vector:   v,
+		Space:    sp,
+		Tree:     tree,
+		Delta:    physics.NewVector(0, 0),
+		Solid:    solid,
+		MaxSlope: 45,
+	}, nil
+}
+
+// Jump asks the character to jump. The character jumps during the next
+// Update if it is on the ground or has just walked off of it, or during
+// the first Update after it lands within JumpBufferFrames frames.
+func (c *Character) Jump() {
+	c.jumpBuffer = c.JumpBufferFrames + 1
+}
+
+// Update moves the character for one frame: it jumps if asked to and able
+// to, falls, and moves by its delta. Delta is stopped in each direction
+// the character touched a floor, wall or ceiling.
+func (c *Character) Update() {
+	if c.jumpBuffer > 0 {
+		c.jumpBuffer--
+		if c.touch.grounded || c.coyote > 0 {
+			c.Delta.SetY(-c.JumpSpeed)
+			c.jumpBuffer = 0
+			c.coyote = 0
+		}
+	}
+	c.Delta.ShiftY(c.Gravity)
+	c.Move(c.Delta.X(), c.Delta.Y())
+	if (c.touch.grounded && c.Delta.Y() > 0) || (c.touch.onCeiling && c.Delta.Y() < 0) {
+		c.Delta.SetY(0)
+	}
+	if c.touch.wall != 0 && math.Signbit(c.Delta.X()) == (c.touch.wall < 0) {
+		c.Delta.SetX(0)
+	}
+	if c.touch.grounded {
+		c.coyote = c.CoyoteFrames
+	} else if c.coyote > 0 {
+		c.coyote--
+	}
+}
+
+// Move moves the character by (x, y), stopping it against solids and
+// sliding it along them, and updates whether it is on the ground, against
+// a wall or against a ceiling. Characters on the ground do not slide down
+// slopes they can stand on, walk up steps, and follow the ground down
+// slopes and steps.
+func (c *Character) Move(x, y float64) {
+	wasGrounded := c.touch.grounded
+	c.touch = characterTouch{}
+
+	// Motion is split into steps no longer than half of the character's
+	// smaller side, so it cannot pass through thin solids
+	maxStep := math.Min(c.Space.GetW(), c.Space.GetH()) / 2
+	remaining := floatgeom.Point2{x, y}
+	slides := 0
+	for slides < maxCharacterSlides && remaining.Magnitude() > characterEpsilon {
+		step := remaining
+		if l := step.Magnitude(); l > maxStep {
+			step = step.MulConst(maxStep / l)
+		}
+		before := c.Space.Location
+		c.shift(step.X(), step.Y())
+		remaining = remaining.Sub(step)
+		normals := c.resolve(before)
+		if (wasGrounded || c.touch.grounded) && c.hitWall(normals) {
+			if stepped, ok := c.stepUp(before, step.X()); ok {
+				normals = stepped
+			}
+		}
+		if len(normals) > 0 {
+			slides++
+		}
+		for _, n := range normals {
+			if c.isFloor(n) {
+				// Stop falling rather than sliding down the floor
+				if remaining.Y() > 0 {
+					remaining = floatgeom.Point2{remaining.X(), 0}
+				}
+				continue
+			}
+			if d := remaining.Dot(n); d < 0 {
+				remaining = remaining.Sub(n.MulConst(d))
+			}
+		}
+	}
+
+	if wasGrounded && !c.touch.grounded && y >= 0 {
+		c.snapDown(c.StepHeight + math.Abs(x)*math.Tan(c.MaxSlope*alg.DegToRad))
+	}
+	c.Vector.SetPos(c.Space.X(), c.Space.Y())
+}
+
+// Grounded returns whether the character was standing on something after
+// its last move.
+func (c *Character) Grounded() bool {
+	return c.touch.grounded
+}
+
+// Floor returns what the character was standing on after its last move,
+// and the unit normal of its surface, or nil if it was not standing on
+// anything.
+func (c *Character) Floor() (*collision.Space, physics.Vector) {
+	return c.touch.floor, physics.NewVector(c.touch.floorNormal.X(), c.touch.floorNormal.Y())
+}
+
+// OnCeiling returns whether the character hit a ceiling during its last
+// move.
+func (c *Character) OnCeiling() bool {
+	return c.touch.onCeiling
+}
+
+// OnWall returns -1 if the character touched a wall to its left during its
+// last move, 1 if it touched a wall to its right, and 0 otherwise.
+func (c *Character) OnWall() int {
+	return c.touch.wall
+}
+
+func (c *Character) shift(x, y float64) {
+	loc := c.Space.Location
+	loc.Min[0] += x
+	loc.Max[0] += x
+	loc.Min[1] += y
+	loc.Max[1] += y
+	c.Tree.UpdateSpaceRect(loc, c.Space)
+}
+
+func (c *Character) isFloor(n floatgeom.Point2) bool {
+	return -n.Y() >= math.Cos(c.MaxSlope*alg.DegToRad)-characterEpsilon
+}
+
+func (c *Character) isCeiling(n floatgeom.Point2) bool {
+	return n.Y() >= math.Cos(c.MaxSlope*alg.DegToRad)-characterEpsilon
+}
+
+func (c *Character) hitWall(normals []floatgeom.Point2) bool {
+	for _, n := range normals {
+		if !c.isFloor(n) && !c.isCeiling(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// solids returns a filter for the spaces the character cannot pass
+// through after moving from before. One-way platforms are only solid if
+// the character moved down onto them from above. If before is nil, one-way
+// platforms are never solid.
+func (c *Character) solids(before *floatgeom.Rect3) collision.Filter {
+	return func(sps []*collision.Space) []*collision.Space {
+		var out []*collision.Space
+		for _, sp := range sps {
+			if sp == nil {
+				continue
+			}
+			if sp.HasLabel(c.OneWay...) {
+				if before != nil && before.Max[1] <= sp.Location.Min[1]+characterEpsilon &&
+					c.Space.Location.Max[1] > before.Max[1] {
+					out = append(out, sp)
+				}
+				continue
+			}
+			if len(c.Solid) == 0 || sp.HasLabel(c.Solid...) {
+				out = append(out, sp)
+			}
+		}
+		return out
+	}
+}
+
+// resolve pushes the character out of the solids it overlaps after
+// moving from before, records what it touched, and returns the normals of
+// what it touched.
+func (c *Character) resolve(before floatgeom.Rect3) []floatgeom.Point2 {
+	var normals []floatgeom.Point2
+	for i := 0; i < maxCharacterSlides; i++ {
+		contacts := c.Tree.Contacts(c.Space, c.solids(&before))
+		if len(contacts) == 0 {
+			break
+		}
+		deepest := contacts[0]
+		for _, ct := range contacts[1:] {
+			if ct.MTV.Magnitude() > deepest.MTV.Magnitude() {
+				deepest = ct
+			}
+		}
+		mtv := floatgeom.Point2{deepest.MTV.X(), deepest.MTV.Y()}
+		n := mtv.DivConst(mtv.Magnitude())
+		switch {
+		case deepest.Space.HasLabel(c.OneWay...):
+			n = floatgeom.Point2{0, -1}
+			c.shift(0, deepest.Space.Location.Min[1]-c.Space.Location.Max[1])
+		case c.isFloor(n):
+			// Floors push characters straight up, so characters do not
+			// slide down slopes they are standing on
+			c.shift(0, mtv.Magnitude()/n.Y())
+		default:
+			c.shift(mtv.X(), mtv.Y())
+		}
+		switch {
+		case c.isFloor(n):
+			c.touch.grounded = true
+			c.touch.floor = deepest.Space
+			c.touch.floorNormal = n
+		case c.isCeiling(n):
+			c.touch.onCeiling = true
+		case n.X() > 0:
+			c.touch.wall = -1
+		default:
+			c.touch.wall = 1
+		}
+		normals = append(normals, n)
+	}
+	return normals
+}
+
+// stepUp tries to move the character from before by x, up and over a step
+// at most StepHeight tall, and returns the normals of what it landed on. If
+// it cannot, the character is left where it was.
+func (c *Character) stepUp(before floatgeom.Rect3, x float64) ([]floatgeom.Point2, bool) {
+	if c.StepHeight <= 0 || x == 0 {
+		return nil, false
+	}
+	after, touch := c.Space.Location, c.touch
+	c.Tree.UpdateSpaceRect(before, c.Space)
+	c.shift(0, -c.StepHeight)
+	if len(c.Tree.Contacts(c.Space, c.solids(nil))) == 0 {
+		c.shift(x, 0)
+		if len(c.Tree.Contacts(c.Space, c.solids(nil))) == 0 {
+			// Land on the step, sinking into it slightly so that landing
+			// on it is found
+			down := c.StepHeight
+			if hit, ok := c.Tree.Sweep(c.Space, physics.NewVector(0, down), c.solids(nil)); ok {
+				down = down*hit.Time + stepSink
+			}
+			raised := c.Space.Location
+			c.shift(0, down)
+			c.touch = characterTouch{}
+			normals := c.resolve(raised)
+			// Steep slopes are not steps
+			if c.touch.grounded && !c.hitWall(normals) {
+				return normals, true
+			}
+		}
+	}
+	c.Tree.UpdateSpaceRect(after, c.Space)
+	c.touch = touch
+	return nil, false
+}
+
+// snapDown moves the character down by at most distance onto a floor, if
+// there is one within that distance.
+func (c *Character) snapDown(distance float64) {
+	if distance <= 0 {
+		return
+	}
+	before, touch := c.Space.Location, c.touch
+	c.shift(0, distance)
+	c.resolve(before)
+	if c.touch.grounded {
+		return
+	}
+	c.Tree.UpdateSpaceRect(before, c.Space)
+	c.touch = touch
+}
@@ -0,0 +1,211 @@
+package entities
+
+import (
+	"math"
+	"testing"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testGround collision.Label = iota + 1
+	testPlatform
+)
+
+// newTestCharacter returns a 10x20 character standing on a floor whose top
+// is at y=100.
+func newTestCharacter(t *testing.T, x float64) (*Character, *collision.Tree) {
+	tree, err := collision.NewTree()
+	assert.Nil(t, err)
+	tree.Add(collision.NewLabeledSpace(-1000, 100, 2000, 20, testGround))
+	sp := collision.NewUnassignedSpace(x, 80, 10, 20)
+	tree.Add(sp)
+	c, err := NewCharacter(sp, physics.NewVector(0, 0), tree, testGround)
+	assert.Nil(t, err)
+	c.Gravity = .5
+	c.JumpSpeed = 8
+	c.Update()
+	assert.True(t, c.Grounded())
+	return c, tree
+}
+
+func TestCharacterErrors(t *testing.T) {
+	_, err := NewCharacter(nil, physics.NewVector(0, 0), nil)
+	assert.Equal(t, oakerr.NilInput{InputName: "sp"}, err)
+}
+
+func TestCharacterLanding(t *testing.T) {
+	c, _ := newTestCharacter(t, 0)
+	c.Tree.UpdateSpace(0, 0, 10, 20, c.Space)
+	for i := 0; i < 60; i++ {
+		c.Update()
+	}
+	assert.True(t, c.Grounded())
+	assert.Equal(t, 80.0, c.Y())
+	assert.Equal(t, 0.0, c.Delta.Y())
+	floor, normal := c.Floor()
+	assert.Equal(t, testGround, floor.Label)
+	assert.Equal(t, physics.NewVector(0, -1), normal)
+
+	// Spaces without solid labels are passed through
+	c.Tree.Add(collision.NewLabeledSpace(-100, 60, 200, 10, testPlatform))
+	c.Tree.UpdateSpace(0, 0, 10, 20, c.Space)
+	for i := 0; i < 60; i++ {
+		c.Update()
+	}
+	assert.Equal(t, 80.0, c.Y())
+}
+
+func TestCharacterWallsAndCeilings(t *testing.T) {
+	c, tree := newTestCharacter(t, 0)
+	tree.Add(collision.NewLabeledSpace(50, 0, 10, 100, testGround))
+	for i := 0; i < 20; i++ {
+		c.Delta.SetX(3)
+		c.Update()
+	}
+	assert.Equal(t, 40.0, c.X())
+	assert.Equal(t, 1, c.OnWall())
+	assert.Equal(t, 0.0, c.Delta.X())
+	assert.True(t, c.Grounded())
+
+	tree.Add(collision.NewLabeledSpace(-100, 50, 145, 10, testGround))
+	c.Jump()
+	c.Update()
+	for i := 0; i < 5 && !c.OnCeiling(); i++ {
+		c.Update()
+	}
+	assert.True(t, c.OnCeiling())
+	assert.Equal(t, 60.0, c.Y())
+	assert.True(t, c.Delta.Y() >= 0)
+}
+
+func TestCharacterSlopes(t *testing.T) {
+	c, tree := newTestCharacter(t, 0)
+	// A 30 degree slope up to the right
+	gentle, err := collision.NewPolygonSpace([]floatgeom.Point2{
+		{20, 100}, {120, 100}, {120, 100 - 100*math.Tan(math.Pi/6)},
+	}, testGround, 0)
+	assert.Nil(t, err)
+	tree.Add(gentle)
+	c.Delta.SetX(2)
+	for i := 0; i < 30; i++ {
+		c.Update()
+	}
+	assert.True(t, c.Grounded())
+	assert.True(t, c.Y() < 80)
+	_, normal := c.Floor()
+	assert.InDelta(t, -30, normal.Angle()+90, .1)
+	assert.Equal(t, 2.0, c.Delta.X())
+
+	// Characters do not slide down slopes they stand on
+	c.Delta.SetX(0)
+	c.Update()
+	x, y := c.GetPos()
+	for i := 0; i < 10; i++ {
+		c.Update()
+	}
+	assert.InDelta(t, x, c.X(), .001)
+	assert.InDelta(t, y, c.Y(), .001)
+
+	c2, tree2 := newTestCharacter(t, 0)
+	steep, err := collision.NewPolygonSpace([]floatgeom.Point2{
+		{20, 100}, {120, 100}, {120, 100 - 100*math.Tan(math.Pi/3)},
+	}, testGround, 0)
+	assert.Nil(t, err)
+	tree2.Add(steep)
+	for i := 0; i < 30; i++ {
+		c2.Delta.SetX(2)
+		c2.Update()
+	}
+	assert.Equal(t, 1, c2.OnWall())
+	assert.True(t, c2.X() < 20)
+}
+
+func TestCharacterSteps(t *testing.T) {
+	c, tree := newTestCharacter(t, 0)
+	c.StepHeight = 6
+	tree.Add(collision.NewLabeledSpace(20, 95, 20, 5, testGround))
+	tree.Add(collision.NewLabeledSpace(60, 90, 20, 10, testGround))
+	c.Delta.SetX(2)
+	for i := 0; i < 22; i++ {
+		c.Update()
+	}
+	// Characters walk up short steps, and follow them down
+	assert.True(t, c.X() > 40)
+	assert.Equal(t, 80.0, c.Y())
+	assert.True(t, c.Grounded())
+	for i := 0; i < 10; i++ {
+		c.Delta.SetX(2)
+		c.Update()
+	}
+	// But not tall steps
+	assert.Equal(t, 50.0, c.X())
+	assert.Equal(t, 1, c.OnWall())
+}
+
+func TestCharacterOneWay(t *testing.T) {
+	c, tree := newTestCharacter(t, 0)
+	c.OneWay = []collision.Label{testPlatform}
+	tree.Add(collision.NewLabeledSpace(-100, 50, 200, 5, testPlatform))
+	c.Jump()
+	for i := 0; i < 60; i++ {
+		c.Update()
+	}
+	assert.True(t, c.Grounded())
+	assert.Equal(t, 30.0, c.Y())
+	floor, _ := c.Floor()
+	assert.Equal(t, testPlatform, floor.Label)
+}
+
+func TestCharacterCoyoteTime(t *testing.T) {
+	c, _ := newTestCharacter(t, 995)
+	c.CoyoteFrames = 3
+	c.Delta.SetX(3)
+	c.Update()
+	c.Update()
+	assert.False(t, c.Grounded())
+	c.Jump()
+	c.Update()
+	assert.True(t, c.Delta.Y() < 0)
+
+	c, _ = newTestCharacter(t, 995)
+	c.CoyoteFrames = 1
+	c.Delta.SetX(3)
+	for i := 0; i < 3; i++ {
+		c.Update()
+	}
+	c.Jump()
+	c.Update()
+	assert.True(t, c.Delta.Y() > 0)
+}
+
+func TestCharacterJumpBuffer(t *testing.T) {
+	c, _ := newTestCharacter(t, 0)
+	c.JumpBufferFrames = 3
+	c.Tree.UpdateSpace(0, 76, 10, 20, c.Space)
+	c.Update()
+	assert.False(t, c.Grounded())
+	// Asking to jump in the air jumps upon landing
+	c.Jump()
+	for i := 0; i < 3 && !c.Grounded(); i++ {
+		c.Update()
+	}
+	assert.True(t, c.Grounded())
+	c.Update()
+	assert.True(t, c.Delta.Y() < 0)
+
+	// But not if asked too long before
+	c.Tree.UpdateSpace(0, 0, 10, 20, c.Space)
+	c.Delta.SetY(0)
+	c.Update()
+	c.Jump()
+	for !c.Grounded() {
+		c.Update()
+	}
+	c.Update()
+	assert.True(t, c.Grounded())
+}
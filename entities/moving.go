@@ -38,6 +38,22 @@ func (m *Moving) ShiftVector(v physics.Vector) {
 	m.Solid.ShiftPos(v.X(), v.Y())
 }
 
+// SweepDelta moves a moving by its delta as Solid.SweepMove does. Each
+// component of the delta which was stopped by a space is zeroed, so a moving
+// that lands on a floor stops falling, but keeps moving along it.
+func (m *Moving) SweepDelta(fs ...collision.Filter) []collision.SweepHit {
+	hits := m.Solid.SweepMove(m.Delta.X(), m.Delta.Y(), fs...)
+	for _, h := range hits {
+		if h.Normal.X() != 0 {
+			m.Delta.SetX(0)
+		}
+		if h.Normal.Y() != 0 {
+			m.Delta.SetY(0)
+		}
+	}
+	return hits
+}
+
 // ApplyFriction modifies a moving's delta by combining
 // environmental friction with the moving's base friction
 // and scaling down the delta by the combined result.
@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/dlog"
 	"github.com/oakmound/oak/event"
 	"github.com/oakmound/oak/render"
 )
@@ -18,7 +19,8 @@ type Reactive struct {
 }
 
 // NewReactive returns a new Reactive struct. The added space will
-// be added to the input tree, or DefTree if none is given.
+// be added to the input tree, or DefTree if none is given. The space is
+// attached to the reactive's CID as a *collision.ReactiveSpace component.
 func NewReactive(x, y, w, h float64, r render.Renderable, tree *collision.Tree, cid event.CID) *Reactive {
 	rct := Reactive{}
 	cid = cid.Parse(&rct)
@@ -31,6 +33,7 @@ func NewReactive(x, y, w, h float64, r render.Renderable, tree *collision.Tree,
 	}
 	rct.Tree = tree
 	rct.Tree.Add(rct.RSpace.Space)
+	dlog.ErrorCheck(cid.AddComponent(rct.RSpace))
 	return &rct
 }
 
@@ -59,6 +62,7 @@ func (r *Reactive) SetSpace(sp *collision.ReactiveSpace) {
 	r.Tree.Remove(r.RSpace.Space)
 	r.RSpace = sp
 	r.Tree.Add(r.RSpace.Space)
+	dlog.ErrorCheck(r.CID.AddComponent(r.RSpace))
 }
 
 // GetSpace returns this reactive's space underlying its RSpace
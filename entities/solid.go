@@ -4,7 +4,9 @@ import (
 	"strconv"
 
 	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/dlog"
 	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/physics"
 	"github.com/oakmound/oak/render"
 )
 
@@ -18,7 +20,8 @@ type Solid struct {
 
 // NewSolid returns an initialized Solid that is not drawn and whose space
 // belongs to the given collision tree. If nil is given as the tree, it will
-// belong to collision.DefTree
+// belong to collision.DefTree. The space is attached to the solid's CID as a
+// *collision.Space component.
 func NewSolid(x, y, w, h float64, r render.Renderable, tree *collision.Tree, cid event.CID) *Solid {
 	s := Solid{}
 	cid = cid.Parse(&s)
@@ -31,6 +34,7 @@ func NewSolid(x, y, w, h float64, r render.Renderable, tree *collision.Tree, cid
 	s.Tree = tree
 	s.Space = collision.NewSpace(x, y, w, h, cid)
 	s.Tree.Add(s.Space)
+	dlog.ErrorCheck(cid.AddComponent(s.Space))
 	return &s
 }
 
@@ -57,6 +61,7 @@ func (s *Solid) SetSpace(sp *collision.Space) {
 	s.Tree.Remove(s.Space)
 	s.Space = sp
 	s.Tree.Add(s.Space)
+	dlog.ErrorCheck(s.CID.AddComponent(s.Space))
 }
 
 // GetSpace returns a solid's collision space
@@ -98,6 +103,16 @@ func (s *Solid) HitLabel(classtype collision.Label) *collision.Space {
 	return s.Tree.HitLabel(s.Space, classtype)
 }
 
+// SweepMove moves a solid by (x,y), stopping it against and sliding it along
+// any spaces in its Tree that pass the given filters, even if the motion
+// would otherwise pass entirely through them. It returns the spaces the
+// solid touched, in order. See collision.Tree.SweepMove.
+func (s *Solid) SweepMove(x, y float64, fs ...collision.Filter) []collision.SweepHit {
+	moved, hits := s.Tree.SweepMove(s.Space, physics.NewVector(x, y), fs...)
+	s.SetPos(s.X()+moved.X(), s.Y()+moved.Y())
+	return hits
+}
+
 // Overwrites
 
 // Init satisfies event.Entity
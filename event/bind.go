@@ -1,6 +1,10 @@
 package event
 
-import "github.com/oakmound/oak/dlog"
+import (
+	"sync/atomic"
+
+	"github.com/oakmound/oak/dlog"
+)
 
 // BindPriority is called by entities. Entities pass in a bindable function,
 // and a set of options which are parsed out.
@@ -30,3 +34,20 @@ func (eb *Bus) Bind(fn Bindable, name string, callerID int) {
 func (eb *Bus) GlobalBind(fn Bindable, name string) {
 	eb.Bind(fn, name, 0)
 }
+
+// EveryFrame binds fn to be called at the start of every frame, until the
+// returned stop function is called. Calling stop more than once does
+// nothing.
+func (eb *Bus) EveryFrame(fn func()) (stop func()) {
+	var stopped int32
+	eb.GlobalBind(func(int, interface{}) int {
+		if atomic.LoadInt32(&stopped) == 1 {
+			return UnbindSingle
+		}
+		fn()
+		return 0
+	}, Enter)
+	return func() {
+		atomic.StoreInt32(&stopped, 1)
+	}
+}
@@ -3,6 +3,7 @@ package event
 import (
 	"reflect"
 	"sync"
+	"sync/atomic"
 
 	"github.com/oakmound/oak/timing"
 )
@@ -17,6 +18,9 @@ type Bindable func(int, interface{}) int
 // operate efficiently
 type bindableList struct {
 	sl []Bindable
+	// orders stores the order in which each bindable in sl was
+	// bound, relative to all other bindables on the bus.
+	orders []int64
 	// We keep track of where the next nil
 	// element in our list is, so we
 	// can let bindings know where they
@@ -47,16 +51,25 @@ type bindableStore struct {
 
 // A Bus stores bindables to be triggered by events
 type Bus struct {
+	// framesElapsed is accessed atomically, and is kept first so that it
+	// is 64-bit aligned on 32-bit platforms.
+	framesElapsed       int64
 	bindingMap          map[string]map[int]*bindableStore
 	doneCh              chan bool
 	updateCh            chan bool
-	framesElapsed       int
 	Ticker              *timing.DynamicTicker
 	binds               []UnbindOption
 	partUnbinds         []BindingOption
 	fullUnbinds         []UnbindOption
 	unbinds             []binding
 	unbindAllAndRebinds []UnbindAllOption
+	// bindOrder counts how many bindables have been stored on the bus.
+	bindOrder int64
+	// synchronous is 1 if triggers should be dispatched synchronously.
+	synchronous int32
+	// profiling is 1 if event statistics should be recorded in profile.
+	profiling int32
+	profile   profile
 
 	mutex        sync.RWMutex
 	pendingMutex sync.Mutex
@@ -108,11 +121,12 @@ type binding struct {
 	index int
 }
 
-// Reset empties out all transient portions of the bus. It will not stop
-// an ongoing loop.
+// Reset empties out all transient portions of the bus, including
+// its count of elapsed frames. It will not stop an ongoing loop.
 func (eb *Bus) Reset() {
 	eb.mutex.Lock()
 	eb.pendingMutex.Lock()
+	atomic.StoreInt64(&eb.framesElapsed, 0)
 	eb.bindingMap = make(map[string]map[int]*bindableStore)
 	eb.binds = []UnbindOption{}
 	eb.partUnbinds = []BindingOption{}
@@ -130,14 +144,17 @@ type UnbindAllOption struct {
 	bnds []Bindable
 }
 
-// Store a bindable into a BindableList.
-func (bl *bindableList) storeBindable(fn Bindable) int {
+// Store a bindable into a BindableList, recording that it was
+// bound in the given order.
+func (bl *bindableList) storeBindable(fn Bindable, order int64) int {
 
 	i := bl.nextEmpty
 	if len(bl.sl) == i {
 		bl.sl = append(bl.sl, fn)
+		bl.orders = append(bl.orders, order)
 	} else {
 		bl.sl[i] = fn
+		bl.orders[i] = order
 	}
 
 	// Find the next empty space
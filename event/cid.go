@@ -1,8 +1,41 @@
 package event
 
-// A CID is a caller ID that entities use to trigger and bind functionality
+import "strconv"
+
+// A CID is a caller ID that entities use to trigger and bind functionality.
+// The low bits of a CID are the index of its entity's slot, and the bits
+// above those are the generation of that slot, which advances each time the
+// slot's entity is destroyed. The first entity in each slot has a CID equal
+// to its index.
+//
+// Where int is 64 bits, 32 bits are used for the index and 31 for the
+// generation. Where int is 32 bits, 20 bits are used for the index and 11
+// for the generation, so generations repeat after 2048 entities have been
+// destroyed in the same slot.
 type CID int
 
+const (
+	indexBits      = 20 + 12*(strconv.IntSize/64)
+	generationBits = strconv.IntSize - 1 - indexBits
+	indexMask      = 1<<indexBits - 1
+	generationMask = 1<<generationBits - 1
+)
+
+func newCID(index int, generation uint32) CID {
+	return CID(int(generation&generationMask)<<indexBits | index&indexMask)
+}
+
+// Index returns the index of the entity slot the cid refers to.
+func (cid CID) Index() int {
+	return int(cid) & indexMask
+}
+
+// Generation returns the generation of the entity slot the cid refers to.
+// If the slot's current generation differs, the cid is stale.
+func (cid CID) Generation() uint32 {
+	return uint32(int(cid)>>indexBits) & generationMask
+}
+
 // E is shorthand for GetEntity(int(cid))
 // But we apparently forgot we added this shorthand,
 // because this isn't used anywhere.
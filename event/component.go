@@ -0,0 +1,127 @@
+package event
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/oakmound/oak/oakerr"
+)
+
+var (
+	// components stores each entity's components by component type
+	components    = make(map[reflect.Type]map[CID]interface{})
+	componentLock = sync.RWMutex{}
+)
+
+// AddComponent attaches c to the entity with this cid, replacing any
+// component the entity had of the same type. Components are removed
+// when their entity is destroyed or reset.
+func (cid CID) AddComponent(c interface{}) error {
+	if c == nil {
+		return oakerr.NilInput{InputName: "c"}
+	}
+	idMutex.RLock()
+	defer idMutex.RUnlock()
+	if !hasEntity(cid) {
+		return oakerr.NotFound{InputName: "cid"}
+	}
+	typ := reflect.TypeOf(c)
+	componentLock.Lock()
+	if components[typ] == nil {
+		components[typ] = make(map[CID]interface{})
+	}
+	components[typ][cid] = c
+	componentLock.Unlock()
+	return nil
+}
+
+// Component sets the value target points to to the entity's component of
+// that value's type, returning whether the entity had such a component.
+// To get a *collision.Space component, for example, target should be a
+// **collision.Space.
+func (cid CID) Component(target interface{}) bool {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return false
+	}
+	componentLock.RLock()
+	c, ok := components[v.Type().Elem()][cid]
+	componentLock.RUnlock()
+	if ok {
+		v.Elem().Set(reflect.ValueOf(c))
+	}
+	return ok
+}
+
+// HasComponents returns whether the entity has a component of the type of
+// each given example. Examples are typically zero values, like
+// (*collision.Space)(nil).
+func (cid CID) HasComponents(examples ...interface{}) bool {
+	componentLock.RLock()
+	defer componentLock.RUnlock()
+	for _, ex := range examples {
+		if _, ok := components[reflect.TypeOf(ex)][cid]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// RemoveComponent removes the entity's component of the same type as the
+// given example, if it has one.
+func (cid CID) RemoveComponent(example interface{}) {
+	componentLock.Lock()
+	delete(components[reflect.TypeOf(example)], cid)
+	componentLock.Unlock()
+}
+
+// EntitiesWith returns the ids of all entities which have components of the
+// types of all given examples, in order of their index. If no examples are
+// given, nothing is returned.
+func EntitiesWith(examples ...interface{}) []CID {
+	if len(examples) == 0 {
+		return nil
+	}
+	componentLock.RLock()
+	sets := make([]map[CID]interface{}, len(examples))
+	smallest := 0
+	for i, ex := range examples {
+		sets[i] = components[reflect.TypeOf(ex)]
+		if len(sets[i]) < len(sets[smallest]) {
+			smallest = i
+		}
+	}
+	var cids []CID
+	for cid := range sets[smallest] {
+		has := true
+		for _, set := range sets {
+			if _, ok := set[cid]; !ok {
+				has = false
+				break
+			}
+		}
+		if has {
+			cids = append(cids, cid)
+		}
+	}
+	componentLock.RUnlock()
+	sort.Slice(cids, func(i, j int) bool {
+		return cids[i].Index() < cids[j].Index()
+	})
+	return cids
+}
+
+// removeComponents removes all components of entities matching the given
+// function.
+func removeComponents(matches func(CID) bool) {
+	componentLock.Lock()
+	for _, set := range components {
+		for cid := range set {
+			if matches(cid) {
+				delete(set, cid)
+			}
+		}
+	}
+	componentLock.Unlock()
+}
@@ -0,0 +1,61 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/oakerr"
+	"github.com/stretchr/testify/assert"
+)
+
+type position struct {
+	X, Y float64
+}
+
+type health int
+
+func TestComponents(t *testing.T) {
+	ResetEntities()
+	cid := ent{}.Init()
+	cid2 := ent{}.Init()
+	cid3 := ent{}.Init()
+
+	assert.Nil(t, cid.AddComponent(&position{1, 2}))
+	assert.Nil(t, cid.AddComponent(health(10)))
+	assert.Nil(t, cid2.AddComponent(&position{3, 4}))
+	assert.Nil(t, cid3.AddComponent(health(5)))
+	assert.Nil(t, cid3.AddComponent(&position{5, 6}))
+	_, ok := cid.AddComponent(nil).(oakerr.NilInput)
+	assert.True(t, ok)
+
+	var pos *position
+	assert.True(t, cid2.Component(&pos))
+	assert.Equal(t, &position{3, 4}, pos)
+	var h health
+	assert.False(t, cid2.Component(&h))
+	assert.False(t, cid2.Component(h))
+	assert.True(t, cid.Component(&h))
+	assert.Equal(t, health(10), h)
+
+	assert.True(t, cid.HasComponents((*position)(nil), health(0)))
+	assert.False(t, cid2.HasComponents((*position)(nil), health(0)))
+
+	assert.Equal(t, []CID{cid, cid2, cid3}, EntitiesWith((*position)(nil)))
+	assert.Equal(t, []CID{cid, cid3}, EntitiesWith((*position)(nil), health(0)))
+	assert.Nil(t, EntitiesWith())
+
+	cid3.RemoveComponent(health(0))
+	assert.Equal(t, []CID{cid}, EntitiesWith(health(0)))
+
+	// Components are dropped with their entities
+	DestroyEntity(int(cid))
+	assert.Equal(t, []CID{cid2, cid3}, EntitiesWith((*position)(nil)))
+	_, ok = cid.AddComponent(health(1)).(oakerr.NotFound)
+	assert.True(t, ok)
+	cid4 := ent{}.Init()
+	assert.False(t, cid4.HasComponents(health(0)))
+
+	ResetEntitiesAfter(cid2)
+	assert.Equal(t, []CID{cid2}, EntitiesWith((*position)(nil)))
+	ResetEntities()
+	assert.Empty(t, EntitiesWith((*position)(nil)))
+}
@@ -11,8 +11,16 @@ var (
 
 // Trigger an event, but only for one ID, on the default bus
 func (cid CID) Trigger(eventName string, data interface{}) {
+	if !validTrigger(eventName, data) {
+		return
+	}
+	if DefaultBus.Synchronous() {
+		DefaultBus.triggerSync(eventName, data, DefaultBus.orderedBindings(eventName, int(cid), true))
+		return
+	}
 
 	go func(eventName string, data interface{}) {
+		DefaultBus.recordTrigger(eventName)
 		DefaultBus.mutex.RLock()
 		iid := int(cid)
 		if idMap, ok := DefaultBus.bindingMap[eventName]; ok {
@@ -20,15 +28,15 @@ func (cid CID) Trigger(eventName string, data interface{}) {
 				for i := bs.highIndex - 1; i >= 0; i-- {
 					lst := bs.highPriority[i]
 					if lst != nil {
-						DefaultBus.triggerDefault((*lst).sl, iid, eventName, data)
+						DefaultBus.triggerDefault((*lst).sl, iid, i+1, eventName, data)
 					}
 				}
-				DefaultBus.triggerDefault((bs.defaultPriority).sl, iid, eventName, data)
+				DefaultBus.triggerDefault((bs.defaultPriority).sl, iid, 0, eventName, data)
 
 				for i := 0; i < bs.lowIndex; i++ {
 					lst := bs.lowPriority[i]
 					if lst != nil {
-						DefaultBus.triggerDefault((*lst).sl, iid, eventName, data)
+						DefaultBus.triggerDefault((*lst).sl, iid, -(i + 1), eventName, data)
 					}
 				}
 			}
@@ -42,6 +50,11 @@ func (cid CID) Bind(fn Bindable, name string) {
 	DefaultBus.Bind(fn, name, int(cid))
 }
 
+// BindTyped on a CID is shorthand for bus.BindTyped(fn, name, cid), on the default bus.
+func (cid CID) BindTyped(fn interface{}, name string) error {
+	return DefaultBus.BindTyped(fn, name, int(cid))
+}
+
 // BindPriority on a CID is shorthand for bus.BindPriority(fn, ...), on the default bus.
 func (cid CID) BindPriority(fn Bindable, name string, priority int) {
 	DefaultBus.BindPriority(fn, BindingOption{
@@ -90,6 +103,16 @@ func GlobalBind(fn Bindable, name string) {
 	DefaultBus.GlobalBind(fn, name)
 }
 
+// EveryFrame calls EveryFrame on the DefaultBus
+func EveryFrame(fn func()) (stop func()) {
+	return DefaultBus.EveryFrame(fn)
+}
+
+// GlobalBindTyped calls GlobalBindTyped on the DefaultBus
+func GlobalBindTyped(fn interface{}, name string) error {
+	return DefaultBus.GlobalBindTyped(fn, name)
+}
+
 // UnbindAll calls UnbindAll on the DefaultBus
 func UnbindAll(opt BindingOption) {
 	DefaultBus.UnbindAll(opt)
@@ -110,6 +133,11 @@ func Bind(fn Bindable, name string, callerID int) {
 	DefaultBus.Bind(fn, name, callerID)
 }
 
+// BindTyped calls BindTyped on the DefaultBus
+func BindTyped(fn interface{}, name string, callerID int) error {
+	return DefaultBus.BindTyped(fn, name, callerID)
+}
+
 // BindPriority calls BindPriority on the DefaultBus
 func BindPriority(fn Bindable, opt BindingOption) {
 	DefaultBus.BindPriority(fn, opt)
@@ -5,9 +5,18 @@ import (
 )
 
 var (
-	highestID CID
-	callers   = make([]Entity, 0)
-	idMutex   = sync.Mutex{}
+	callers = make([]Entity, 0)
+	// generations stores the generation of each caller slot. It is not
+	// truncated alongside callers by ResetEntitiesAfter, so that slots
+	// which are reassigned afterward still receive new generations.
+	generations = make([]uint32, 0)
+	// freeIDs stores the indices of destroyed caller slots, to be
+	// reassigned to new entities.
+	freeIDs []int
+	// reservedIDs is the highest index of caller slot that will not be
+	// reassigned, even if destroyed. See ReserveEntities.
+	reservedIDs int
+	idMutex     = sync.RWMutex{}
 )
 
 // Todo: callers having assigned buses?
@@ -20,45 +29,136 @@ type Entity interface {
 	Init() CID
 }
 
-// NextID finds the next available caller id (always incrementing)
-// and returns it, after adding the given entity to
-// the slice of callers at the returned index.
+// NextID finds the next available caller id and returns it, after adding the
+// given entity to the slice of callers at the returned id's index. The slots
+// of destroyed entities are reassigned before new slots are added, but with
+// a new generation, so that ids of destroyed entities remain invalid.
 func NextID(e Entity) CID {
 	idMutex.Lock()
-	highestID++
+	defer idMutex.Unlock()
+	for i := len(freeIDs) - 1; i >= 0; i-- {
+		index := freeIDs[i]
+		if index > reservedIDs {
+			freeIDs = append(freeIDs[:i], freeIDs[i+1:]...)
+			callers[index-1] = e
+			return newCID(index, generations[index-1])
+		}
+	}
 	callers = append(callers, e)
-	id := highestID
-	idMutex.Unlock()
-	return id
+	index := len(callers)
+	if len(generations) < index {
+		generations = append(generations, 0)
+	}
+	return newCID(index, generations[index-1])
 }
 
-// GetEntity either returns callers[i-1]
-// or nil, if there is nothing at that index.
+// GetEntity either returns the entity with the given caller id,
+// or nil, if there is no such entity.
 func GetEntity(i int) interface{} {
-	if HasEntity(i) {
-		return callers[i-1]
+	idMutex.RLock()
+	defer idMutex.RUnlock()
+	if !hasEntity(CID(i)) {
+		return nil
 	}
-	return nil
+	return callers[CID(i).Index()-1]
 }
 
-// HasEntity returns whether the given caller id is an initialized entity
+// HasEntity returns whether the given caller id is an initialized entity,
+// which has not since been destroyed or reset.
 func HasEntity(i int) bool {
-	return len(callers) >= i && i != 0
+	idMutex.RLock()
+	defer idMutex.RUnlock()
+	return hasEntity(CID(i))
 }
 
-// DestroyEntity sets the index within the caller list to nil. Note that this
-// does not reduce the size of the caller list, a potential change in the
-// future would be to A) use a map or B) reassign caller ids to not directly
-// correspond to indices within callers
+func hasEntity(cid CID) bool {
+	index := cid.Index()
+	return index > 0 && index <= len(callers) &&
+		callers[index-1] != nil &&
+		generations[index-1]&generationMask == cid.Generation()
+}
+
+// DestroyEntity removes the entity with the given caller id and its
+// components. Its slot will be reassigned to a later entity, with a new
+// generation. Destroying an id that is not a live entity does nothing.
 func DestroyEntity(i int) {
-	callers[i-1] = nil
+	idMutex.Lock()
+	defer idMutex.Unlock()
+	cid := CID(i)
+	if !hasEntity(cid) {
+		return
+	}
+	index := cid.Index()
+	callers[index-1] = nil
+	generations[index-1]++
+	freeIDs = append(freeIDs, index)
+	removeComponents(func(c CID) bool {
+		return c == cid
+	})
 }
 
-// ResetEntities resets callers and highestID, effectively dropping the
-// remaining entities from accessible memory.
+// ResetEntities resets callers and their components, effectively dropping
+// the remaining entities from accessible memory. Caller slots are reassigned
+// from the first index afterward, but with new generations, so that ids from
+// before the reset remain invalid.
 func ResetEntities() {
 	idMutex.Lock()
-	highestID = 0
-	callers = []Entity{}
+	dropSlotsAfter(0)
+	reservedIDs = 0
 	idMutex.Unlock()
 }
+
+// HighestID returns a caller id with the highest index assigned to an
+// entity. If the entity at that index was destroyed, only the index of the
+// returned id is meaningful.
+func HighestID() CID {
+	idMutex.RLock()
+	defer idMutex.RUnlock()
+	if len(callers) == 0 {
+		return 0
+	}
+	return newCID(len(callers), generations[len(callers)-1])
+}
+
+// ResetEntitiesAfter drops all entities with caller ids indexed above the
+// given id, so that those indices will be assigned to new entities. Entities
+// with ids indexed at or below the given id are unaffected.
+func ResetEntitiesAfter(id CID) {
+	idMutex.Lock()
+	if id.Index() < len(callers) {
+		dropSlotsAfter(id.Index())
+	}
+	idMutex.Unlock()
+}
+
+// ReserveEntities prevents the slots of entities with ids indexed at or
+// below the given id from being reassigned when those entities are
+// destroyed, until a lower id is reserved or the entities are reset. This
+// lets a set of entities be kept aside while new entities are created, and
+// then later returned to with ResetEntitiesAfter.
+func ReserveEntities(id CID) {
+	idMutex.Lock()
+	reservedIDs = id.Index()
+	idMutex.Unlock()
+}
+
+// dropSlotsAfter drops all caller slots indexed above index, advancing
+// their generations. idMutex must be held.
+func dropSlotsAfter(index int) {
+	for i := index; i < len(callers); i++ {
+		if callers[i] != nil {
+			generations[i]++
+		}
+	}
+	callers = callers[:index]
+	free := freeIDs[:0]
+	for _, i := range freeIDs {
+		if i <= index {
+			free = append(free, i)
+		}
+	}
+	freeIDs = free
+	removeComponents(func(c CID) bool {
+		return c.Index() > index
+	})
+}
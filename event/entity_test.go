@@ -0,0 +1,65 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityRecycling(t *testing.T) {
+	ResetEntities()
+	cid := ent{}.Init()
+	cid2 := ent{}.Init()
+	assert.Equal(t, 1, cid.Index())
+	assert.Equal(t, 2, cid2.Index())
+
+	DestroyEntity(int(cid))
+	assert.False(t, HasEntity(int(cid)))
+	assert.Nil(t, cid.E())
+	// Destroying a stale id does nothing
+	DestroyEntity(int(cid))
+
+	cid3 := ent{}.Init()
+	assert.Equal(t, cid.Index(), cid3.Index())
+	assert.Equal(t, cid.Generation()+1, cid3.Generation())
+	assert.False(t, HasEntity(int(cid)))
+	assert.True(t, HasEntity(int(cid3)))
+	assert.Equal(t, cid2, HighestID())
+
+	// Reserved slots are not reassigned
+	ReserveEntities(cid2)
+	DestroyEntity(int(cid3))
+	cid4 := ent{}.Init()
+	assert.Equal(t, 3, cid4.Index())
+	ResetEntitiesAfter(cid2)
+	ReserveEntities(0)
+	cid5 := ent{}.Init()
+	assert.Equal(t, cid3.Index(), cid5.Index())
+
+	// Slots are reassigned from the first index after a full reset, with
+	// new generations
+	ResetEntities()
+	assert.False(t, HasEntity(int(cid2)))
+	assert.Equal(t, CID(0), HighestID())
+	cid6 := ent{}.Init()
+	cid7 := ent{}.Init()
+	assert.Equal(t, 1, cid6.Index())
+	assert.Equal(t, 2, cid7.Index())
+	assert.Equal(t, cid2.Generation()+1, cid7.Generation())
+	assert.NotEqual(t, cid2, cid7)
+	assert.False(t, HasEntity(int(cid2)))
+	assert.True(t, HasEntity(int(cid7)))
+	assert.Equal(t, cid7, HighestID())
+}
+
+func TestCIDPacking(t *testing.T) {
+	cid := newCID(5, 3)
+	assert.Equal(t, 5, cid.Index())
+	assert.Equal(t, uint32(3), cid.Generation())
+	assert.True(t, cid > 0)
+	// Generations wrap rather than overflowing into the sign bit
+	cid = newCID(indexMask, generationMask+2)
+	assert.Equal(t, indexMask, cid.Index())
+	assert.Equal(t, uint32(1), cid.Generation())
+	assert.True(t, cid > 0)
+}
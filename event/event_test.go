@@ -264,3 +264,20 @@ func TestBindingSet(t *testing.T) {
 	sleep()
 	assert.Equal(t, triggers, 2)
 }
+
+func TestEveryFrame(t *testing.T) {
+	b := NewBus()
+	calls := 0
+	stop := b.EveryFrame(func() {
+		calls++
+	})
+	b.Flush()
+	<-b.TriggerBack(Enter, 0)
+	<-b.TriggerBack(Enter, 1)
+	assert.Equal(t, 2, calls)
+	stop()
+	stop()
+	<-b.TriggerBack(Enter, 2)
+	<-b.TriggerBack(Enter, 3)
+	assert.Equal(t, 2, calls)
+}
@@ -2,6 +2,7 @@ package event
 
 import (
 	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/oakmound/oak/timing"
@@ -41,7 +42,6 @@ func (eb *Bus) UpdateLoop(framerate int, updateCh chan bool) error {
 	// It then runs any functions bound to when a frame begins.
 	// It then allows a scene to perform it's loop operation.
 	ch := make(chan bool)
-	eb.framesElapsed = 0
 	eb.doneCh = ch
 	eb.updateCh = updateCh
 	go eb.ResolvePending()
@@ -51,8 +51,8 @@ func (eb *Bus) UpdateLoop(framerate int, updateCh chan bool) error {
 		for {
 			select {
 			case <-eb.Ticker.C:
-				<-eb.TriggerBack(Enter, eb.framesElapsed)
-				eb.framesElapsed++
+				<-eb.TriggerBack(Enter, eb.FramesElapsed())
+				atomic.AddInt64(&eb.framesElapsed, 1)
 				eb.updateCh <- true
 			case <-doneCh:
 				eb.Ticker.Stop()
@@ -64,9 +64,11 @@ func (eb *Bus) UpdateLoop(framerate int, updateCh chan bool) error {
 	return nil
 }
 
-// Update updates all entities bound to this handler
+// Update updates all entities bound to this handler, counting
+// as one elapsed frame.
 func (eb *Bus) Update() error {
-	<-eb.TriggerBack(Enter, eb.framesElapsed)
+	<-eb.TriggerBack(Enter, eb.FramesElapsed())
+	atomic.AddInt64(&eb.framesElapsed, 1)
 	return nil
 }
 
@@ -109,9 +111,15 @@ func (eb *Bus) Stop() error {
 	return nil
 }
 
-// FramesElapsed returns how many frames have elapsed since UpdateLoop was last called.
+// FramesElapsed returns how many frames have elapsed since the bus was last Reset.
 func (eb *Bus) FramesElapsed() int {
-	return eb.framesElapsed
+	return int(atomic.LoadInt64(&eb.framesElapsed))
+}
+
+// ResetFrames sets the count of elapsed frames back to zero, without
+// affecting any bindings.
+func (eb *Bus) ResetFrames() {
+	atomic.StoreInt64(&eb.framesElapsed, 0)
 }
 
 // SetTick optionally updates the Logical System’s tick rate
@@ -61,10 +61,13 @@ func TestHandler(t *testing.T) {
 
 	Flush()
 	sleep()
+	frames := FramesElapsed()
 	Update()
 	sleep()
 	sleep()
+	assert.Equal(t, frames+1, FramesElapsed())
 	Reset()
+	assert.Equal(t, 0, FramesElapsed())
 }
 
 func BenchmarkHandler(b *testing.B) {
@@ -79,6 +82,6 @@ func BenchmarkHandler(b *testing.B) {
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		<-DefaultBus.TriggerBack(Enter, DefaultBus.framesElapsed)
+		<-DefaultBus.TriggerBack(Enter, DefaultBus.FramesElapsed())
 	}
 }
@@ -0,0 +1,177 @@
+package event
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/oakmound/oak/dlog"
+	"github.com/oakmound/oak/oakerr"
+)
+
+var (
+	payloadTypes = make(map[string]reflect.Type)
+	payloadLock  = sync.RWMutex{}
+
+	intType = reflect.TypeOf(0)
+)
+
+func init() {
+	RegisterPayload(Enter, 0)
+	RegisterPayload(AnimationEnd, nil)
+	RegisterPayload(ViewportUpdate, []float64{})
+}
+
+// RegisterPayload registers the type of example as the payload type the named
+// event is triggered with. A nil example registers that the event is
+// triggered without a payload. Once registered, triggers of the event with
+// any other type of payload are logged and dropped. Triggers without a
+// payload are always accepted, so that events which were triggered with nil
+// before their payload was registered still are: typed bindings receive the
+// zero value of their type. If a different type was already registered for
+// the event it is overwritten and an error is returned.
+func RegisterPayload(eventName string, example interface{}) error {
+	return RegisterPayloadType(eventName, reflect.TypeOf(example))
+}
+
+// RegisterPayloadType acts as RegisterPayload, but takes the payload type
+// directly. This allows registering interface types, which will accept any
+// payload that implements them.
+func RegisterPayloadType(eventName string, typ reflect.Type) error {
+	payloadLock.Lock()
+	defer payloadLock.Unlock()
+	old, ok := payloadTypes[eventName]
+	payloadTypes[eventName] = typ
+	if ok && old != typ {
+		return oakerr.ExistingElement{
+			InputName:   eventName,
+			InputType:   "payload type",
+			Overwritten: true,
+		}
+	}
+	return nil
+}
+
+// PayloadType returns the payload type registered for the named event, and
+// whether a type was registered. A nil type is registered for events without
+// a payload.
+func PayloadType(eventName string) (reflect.Type, bool) {
+	payloadLock.RLock()
+	typ, ok := payloadTypes[eventName]
+	payloadLock.RUnlock()
+	return typ, ok
+}
+
+// ValidatePayload returns an error if data is not acceptable as a payload for
+// the named event. Events without a registered payload type accept anything,
+// and every event accepts a nil payload.
+func ValidatePayload(eventName string, data interface{}) error {
+	typ, ok := PayloadType(eventName)
+	if !ok || acceptsPayload(typ, data) {
+		return nil
+	}
+	return oakerr.InvalidPayload{
+		EventName: eventName,
+		Expected:  typeName(typ),
+		Received:  typeName(reflect.TypeOf(data)),
+	}
+}
+
+// validTrigger logs and returns false if data is not a valid payload
+// for the named event.
+func validTrigger(eventName string, data interface{}) bool {
+	if err := ValidatePayload(eventName, data); err != nil {
+		dlog.Error(err)
+		return false
+	}
+	return true
+}
+
+func acceptsPayload(typ reflect.Type, data interface{}) bool {
+	if data == nil {
+		return true
+	}
+	return typ != nil && reflect.TypeOf(data).AssignableTo(typ)
+}
+
+func nillable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map,
+		reflect.Func, reflect.Chan:
+		return true
+	}
+	return false
+}
+
+func typeName(typ reflect.Type) string {
+	if typ == nil {
+		return "nil"
+	}
+	return typ.String()
+}
+
+// TypedBindable converts fn, which must be a function of the form
+// func(int, T) int, into a Bindable which passes its payload to fn as a T.
+// If the named event has a registered payload type, T must be able to hold
+// it. Should a payload which T cannot hold reach the returned Bindable, it
+// will log the mismatch and return Error without calling fn.
+func TypedBindable(eventName string, fn interface{}) (Bindable, error) {
+	if fn == nil {
+		return nil, oakerr.NilInput{InputName: "fn"}
+	}
+	if bnd, ok := fn.(Bindable); ok {
+		return bnd, nil
+	}
+	if bnd, ok := fn.(func(int, interface{}) int); ok {
+		return bnd, nil
+	}
+	fnVal := reflect.ValueOf(fn)
+	fnType := fnVal.Type()
+	if fnType.Kind() != reflect.Func ||
+		fnType.NumIn() != 2 || fnType.NumOut() != 1 ||
+		fnType.In(0) != intType || fnType.Out(0) != intType {
+		return nil, oakerr.InvalidInput{InputName: "fn"}
+	}
+	in := fnType.In(1)
+	if typ, ok := PayloadType(eventName); ok {
+		if (typ == nil && !nillable(in)) || (typ != nil && !typ.AssignableTo(in)) {
+			return nil, oakerr.InvalidPayload{
+				EventName: eventName,
+				Expected:  typeName(typ),
+				Received:  typeName(in),
+			}
+		}
+	}
+	return func(id int, data interface{}) int {
+		arg := reflect.Zero(in)
+		if data != nil {
+			arg = reflect.ValueOf(data)
+			if !arg.Type().AssignableTo(in) {
+				dlog.Error(oakerr.InvalidPayload{
+					EventName: eventName,
+					Expected:  typeName(in),
+					Received:  typeName(arg.Type()),
+				})
+				return Error
+			}
+		}
+		out := fnVal.Call([]reflect.Value{reflect.ValueOf(id), arg})
+		return int(out[0].Int())
+	}, nil
+}
+
+// BindTyped binds fn, a function of the form func(int, T) int, to the
+// given event and caller id. See TypedBindable.
+func (eb *Bus) BindTyped(fn interface{}, name string, callerID int) error {
+	bnd, err := TypedBindable(name, fn)
+	if err != nil {
+		return err
+	}
+	eb.Bind(bnd, name, callerID)
+	return nil
+}
+
+// GlobalBindTyped binds fn, a function of the form func(int, T) int,
+// on the bus to the cid 0, a non entity. See TypedBindable.
+func (eb *Bus) GlobalBindTyped(fn interface{}, name string) error {
+	return eb.BindTyped(fn, name, 0)
+}
@@ -0,0 +1,101 @@
+package event
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/oakmound/oak/oakerr"
+	"github.com/stretchr/testify/assert"
+)
+
+type payloadStruct struct {
+	X int
+}
+
+func TestRegisterPayload(t *testing.T) {
+	assert.Nil(t, RegisterPayload("Payload", payloadStruct{}))
+	assert.Nil(t, RegisterPayload("Payload", payloadStruct{}))
+	typ, ok := PayloadType("Payload")
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeOf(payloadStruct{}), typ)
+	_, ok = PayloadType("Unregistered")
+	assert.False(t, ok)
+
+	assert.Nil(t, ValidatePayload("Payload", payloadStruct{1}))
+	assert.Nil(t, ValidatePayload("Unregistered", 1))
+	err := ValidatePayload("Payload", 1)
+	assert.Equal(t, oakerr.InvalidPayload{
+		EventName: "Payload",
+		Expected:  "event.payloadStruct",
+		Received:  "int",
+	}, err)
+	// Events can always be triggered without a payload
+	assert.Nil(t, ValidatePayload("Payload", nil))
+	assert.Nil(t, ValidatePayload(Enter, nil))
+
+	assert.Nil(t, RegisterPayload("NoPayload", nil))
+	assert.Nil(t, ValidatePayload("NoPayload", nil))
+	assert.NotNil(t, ValidatePayload("NoPayload", 1))
+
+	assert.Nil(t, RegisterPayloadType("Error", reflect.TypeOf((*error)(nil)).Elem()))
+	assert.Nil(t, ValidatePayload("Error", oakerr.NotFound{}))
+	assert.Nil(t, ValidatePayload("Error", nil))
+	assert.NotNil(t, ValidatePayload("Error", 1))
+
+	_, ok = RegisterPayload("Error", 1).(oakerr.ExistingElement)
+	assert.True(t, ok)
+}
+
+func TestTriggerInvalidPayload(t *testing.T) {
+	triggers := 0
+	go ResolvePending()
+	assert.Nil(t, RegisterPayload("Checked", 0))
+	GlobalBind(func(int, interface{}) int {
+		triggers++
+		return 0
+	}, "Checked")
+	sleep()
+	<-TriggerBack("Checked", "not an int")
+	assert.Equal(t, 0, triggers)
+	<-TriggerBack("Checked", 1)
+	assert.Equal(t, 1, triggers)
+	Trigger("Checked", nil)
+	sleep()
+	assert.Equal(t, 2, triggers)
+}
+
+func TestBindTyped(t *testing.T) {
+	go ResolvePending()
+	assert.Nil(t, RegisterPayload("Typed", payloadStruct{}))
+
+	var got payloadStruct
+	assert.Nil(t, GlobalBindTyped(func(_ int, p payloadStruct) int {
+		got = p
+		return 0
+	}, "Typed"))
+	assert.NotNil(t, GlobalBindTyped(func(_ int, i int) int {
+		return 0
+	}, "Typed"))
+	assert.NotNil(t, GlobalBindTyped(func(i int) int {
+		return 0
+	}, "Typed"))
+	assert.NotNil(t, GlobalBindTyped(nil, "Typed"))
+	assert.Nil(t, GlobalBindTyped(func(int, interface{}) int {
+		return 0
+	}, "Typed"))
+	sleep()
+	<-TriggerBack("Typed", payloadStruct{5})
+	assert.Equal(t, payloadStruct{5}, got)
+	// Typed bindings receive the zero value without a payload
+	<-TriggerBack("Typed", nil)
+	assert.Equal(t, payloadStruct{}, got)
+
+	// Unregistered events check payloads as they arrive
+	bnd, err := TypedBindable("Untyped", func(_ int, s string) int {
+		return UnbindSingle
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, UnbindSingle, bnd(0, "string"))
+	assert.Equal(t, Error, bnd(0, 1))
+	assert.Equal(t, UnbindSingle, bnd(0, nil))
+}
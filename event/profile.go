@@ -0,0 +1,179 @@
+package event
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A BindingCount is the number of bindables bound to an event for a
+// single caller at a single priority.
+type BindingCount struct {
+	BindingOption
+	Count int
+}
+
+// EventStats are the statistics recorded for an event while a bus is
+// profiling.
+type EventStats struct {
+	// Triggers is how many times the event was triggered.
+	Triggers int64
+	// Calls is how many bindables were called for the event.
+	Calls int64
+	// TotalTime is the cumulative time spent in bindables for the event.
+	TotalTime time.Duration
+	// MaxTime is the longest time spent in a single bindable for the event.
+	MaxTime time.Duration
+	// Errors, UnbindEvents and UnbindSingles count how many bindables
+	// returned each of those responses.
+	Errors        int64
+	UnbindEvents  int64
+	UnbindSingles int64
+}
+
+// A Snapshot describes what is bound on a bus, and if the bus is profiling,
+// how the bindables for each event have performed.
+type Snapshot struct {
+	// Bindings is sorted by event name, then caller id, then descending
+	// priority.
+	Bindings []BindingCount
+	// Stats holds the recorded statistics of each event which was triggered
+	// since profiling began.
+	Stats map[string]EventStats
+}
+
+// A profile records EventStats for a bus.
+type profile struct {
+	sync.Mutex
+	stats map[string]*EventStats
+}
+
+// SetProfiling sets whether the bus records EventStats for each event.
+// Profiling times every bindable call, so it should not be left on
+// unless needed.
+func (eb *Bus) SetProfiling(profiling bool) {
+	var v int32
+	if profiling {
+		v = 1
+	}
+	atomic.StoreInt32(&eb.profiling, v)
+}
+
+// Profiling returns whether the bus is recording EventStats.
+func (eb *Bus) Profiling() bool {
+	return atomic.LoadInt32(&eb.profiling) == 1
+}
+
+// ResetProfile drops all EventStats recorded by the bus.
+func (eb *Bus) ResetProfile() {
+	eb.profile.Lock()
+	eb.profile.stats = nil
+	eb.profile.Unlock()
+}
+
+// Snapshot returns the current bindings and recorded EventStats of the bus.
+// Pending bindings which have not yet been flushed are not included.
+func (eb *Bus) Snapshot() Snapshot {
+	var s Snapshot
+	eb.mutex.RLock()
+	for name, stores := range eb.bindingMap {
+		for id, bs := range stores {
+			if bs == nil {
+				continue
+			}
+			for i := 0; i < len(bs.highPriority); i++ {
+				s.Bindings = bs.highPriority[i].appendCount(s.Bindings, name, id, i+1)
+			}
+			s.Bindings = bs.defaultPriority.appendCount(s.Bindings, name, id, 0)
+			for i := 0; i < len(bs.lowPriority); i++ {
+				s.Bindings = bs.lowPriority[i].appendCount(s.Bindings, name, id, -(i + 1))
+			}
+		}
+	}
+	eb.mutex.RUnlock()
+	sort.Slice(s.Bindings, func(i, j int) bool {
+		bi, bj := s.Bindings[i], s.Bindings[j]
+		if bi.Name != bj.Name {
+			return bi.Name < bj.Name
+		}
+		if bi.CallerID != bj.CallerID {
+			return callerLess(bi.CallerID, bj.CallerID)
+		}
+		return bi.Priority > bj.Priority
+	})
+
+	s.Stats = make(map[string]EventStats)
+	eb.profile.Lock()
+	for name, st := range eb.profile.stats {
+		s.Stats[name] = *st
+	}
+	eb.profile.Unlock()
+	return s
+}
+
+func (bl *bindableList) appendCount(counts []BindingCount, name string, id, priority int) []BindingCount {
+	if bl == nil {
+		return counts
+	}
+	count := 0
+	for _, fn := range bl.sl {
+		if fn != nil {
+			count++
+		}
+	}
+	if count == 0 {
+		return counts
+	}
+	return append(counts, BindingCount{
+		BindingOption: BindingOption{
+			Event: Event{
+				Name:     name,
+				CallerID: id,
+			},
+			Priority: priority,
+		},
+		Count: count,
+	})
+}
+
+// eventStats returns the stats of an event. The profile must be locked.
+func (p *profile) eventStats(eventName string) *EventStats {
+	if p.stats == nil {
+		p.stats = make(map[string]*EventStats)
+	}
+	st, ok := p.stats[eventName]
+	if !ok {
+		st = new(EventStats)
+		p.stats[eventName] = st
+	}
+	return st
+}
+
+func (eb *Bus) recordTrigger(eventName string) {
+	if !eb.Profiling() {
+		return
+	}
+	eb.profile.Lock()
+	eb.profile.eventStats(eventName).Triggers++
+	eb.profile.Unlock()
+}
+
+func (eb *Bus) recordCall(eventName string, elapsed time.Duration, response int) {
+	eb.profile.Lock()
+	st := eb.profile.eventStats(eventName)
+	st.Calls++
+	st.TotalTime += elapsed
+	if elapsed > st.MaxTime {
+		st.MaxTime = elapsed
+	}
+	switch response {
+	case Error:
+		st.Errors++
+	case UnbindEvent:
+		st.UnbindEvents++
+	case UnbindSingle:
+		st.UnbindSingles++
+	}
+	eb.profile.Unlock()
+}
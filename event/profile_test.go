@@ -0,0 +1,78 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	eb := NewBus()
+	ResetEntities()
+	cid := ent{}.Init()
+	eb.GlobalBind(func(int, interface{}) int {
+		return 0
+	}, "A")
+	eb.GlobalBind(func(int, interface{}) int {
+		return 0
+	}, "A")
+	eb.BindPriority(func(int, interface{}) int {
+		return 0
+	}, BindingOption{Event{"A", int(cid)}, -1})
+	eb.BindPriority(func(int, interface{}) int {
+		return 0
+	}, BindingOption{Event{"A", int(cid)}, 2})
+	eb.Bind(func(int, interface{}) int {
+		return 0
+	}, "B", int(cid))
+	assert.Nil(t, eb.Flush())
+
+	s := eb.Snapshot()
+	assert.Equal(t, []BindingCount{
+		{BindingOption{Event{"A", 0}, 0}, 2},
+		{BindingOption{Event{"A", int(cid)}, 2}, 1},
+		{BindingOption{Event{"A", int(cid)}, -1}, 1},
+		{BindingOption{Event{"B", int(cid)}, 0}, 1},
+	}, s.Bindings)
+	assert.Empty(t, s.Stats)
+}
+
+func TestProfiling(t *testing.T) {
+	eb := NewBus()
+	eb.SetProfiling(true)
+	assert.True(t, eb.Profiling())
+	eb.GlobalBind(func(int, interface{}) int {
+		time.Sleep(10 * time.Millisecond)
+		return 0
+	}, "Slow")
+	eb.GlobalBind(func(int, interface{}) int {
+		return Error
+	}, "Slow")
+	eb.GlobalBind(func(int, interface{}) int {
+		return UnbindSingle
+	}, "Once")
+	assert.Nil(t, eb.Flush())
+
+	<-eb.TriggerBack("Slow", nil)
+	eb.TriggerSync("Slow", nil)
+	<-eb.TriggerBack("Once", nil)
+	// Triggers with no bindables are still counted
+	eb.TriggerSync("Nothing", nil)
+
+	stats := eb.Snapshot().Stats
+	slow := stats["Slow"]
+	assert.Equal(t, int64(2), slow.Triggers)
+	assert.Equal(t, int64(4), slow.Calls)
+	assert.Equal(t, int64(2), slow.Errors)
+	assert.True(t, slow.MaxTime >= 10*time.Millisecond)
+	assert.True(t, slow.TotalTime >= 20*time.Millisecond)
+	assert.Equal(t, int64(1), stats["Once"].UnbindSingles)
+	assert.Equal(t, int64(1), stats["Nothing"].Triggers)
+
+	eb.ResetProfile()
+	assert.Empty(t, eb.Snapshot().Stats)
+	eb.SetProfiling(false)
+	eb.TriggerSync("Slow", nil)
+	assert.Empty(t, eb.Snapshot().Stats)
+}
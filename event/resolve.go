@@ -63,7 +63,8 @@ func (eb *Bus) resolveUnbindAllAndRebinds() {
 			fn := orderedBindables[i]
 			opt := orderedBindOptions[i]
 			list := eb.getBindableList(opt)
-			list.storeBindable(fn)
+			eb.bindOrder++
+			list.storeBindable(fn, eb.bindOrder)
 		}
 	}
 	eb.unbindAllAndRebinds = []UnbindAllOption{}
@@ -131,7 +132,8 @@ func (eb *Bus) resolveBindings() {
 	eb.pendingMutex.Lock()
 	for _, bindSet := range eb.binds {
 		list := eb.getBindableList(bindSet.BindingOption)
-		list.storeBindable(bindSet.Fn)
+		eb.bindOrder++
+		list.storeBindable(bindSet.Fn, eb.bindOrder)
 	}
 	eb.binds = []UnbindOption{}
 	eb.pendingMutex.Unlock()
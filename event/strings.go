@@ -28,4 +28,7 @@ const (
 	// ViewportUpdate: Triggered when the position fo of the viewport changes
 	// Payload: []float64{viewportX, viewportY}
 	ViewportUpdate = "ViewportUpdate"
+	// AssetLoadProgress: Triggered each time an asset from a scene's manifest is loaded
+	// Payload: (oak.LoadProgress) the scene, how many assets have loaded and how many there are
+	AssetLoadProgress = "AssetLoadProgress"
 )
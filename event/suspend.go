@@ -0,0 +1,40 @@
+package event
+
+import "sync/atomic"
+
+// A SuspendedBus stores the bindings and frame count of a Bus
+// that has been suspended, so they can later be resumed.
+type SuspendedBus struct {
+	bindingMap    map[string]map[int]*bindableStore
+	framesElapsed int
+}
+
+// Suspend resolves any pending bindings and then removes and returns all of
+// the bus's bindings and its count of elapsed frames. Nothing bound before
+// Suspend was called will be triggered until the bus is resumed with the
+// returned value.
+func (eb *Bus) Suspend() SuspendedBus {
+	eb.Flush()
+	eb.mutex.Lock()
+	sb := SuspendedBus{
+		bindingMap:    eb.bindingMap,
+		framesElapsed: eb.FramesElapsed(),
+	}
+	eb.bindingMap = make(map[string]map[int]*bindableStore)
+	atomic.StoreInt64(&eb.framesElapsed, 0)
+	eb.mutex.Unlock()
+	return sb
+}
+
+// Resume replaces the bus's bindings and frame count with those of a
+// previously suspended bus. Any bindings on the bus, pending or otherwise,
+// are dropped.
+func (eb *Bus) Resume(sb SuspendedBus) {
+	eb.Reset()
+	eb.mutex.Lock()
+	if sb.bindingMap != nil {
+		eb.bindingMap = sb.bindingMap
+	}
+	atomic.StoreInt64(&eb.framesElapsed, int64(sb.framesElapsed))
+	eb.mutex.Unlock()
+}
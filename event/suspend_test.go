@@ -0,0 +1,51 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuspendResume(t *testing.T) {
+	eb := NewBus()
+	go eb.ResolvePending()
+	triggers := 0
+	eb.GlobalBind(func(int, interface{}) int {
+		triggers++
+		return 0
+	}, "T")
+	sb := eb.Suspend()
+	<-eb.TriggerBack("T", nil)
+	assert.Equal(t, 0, triggers)
+
+	eb.GlobalBind(func(int, interface{}) int {
+		triggers += 10
+		return 0
+	}, "T")
+	sleep()
+	<-eb.TriggerBack("T", nil)
+	assert.Equal(t, 10, triggers)
+
+	eb.Resume(sb)
+	<-eb.TriggerBack("T", nil)
+	assert.Equal(t, 11, triggers)
+}
+
+func TestResetEntitiesAfter(t *testing.T) {
+	ResetEntities()
+	cid := ent{}.Init()
+	cid2 := ent{}.Init()
+	assert.Equal(t, cid2, HighestID())
+	ResetEntitiesAfter(cid)
+	assert.Equal(t, cid, HighestID())
+	assert.True(t, HasEntity(int(cid)))
+	assert.False(t, HasEntity(int(cid2)))
+	// Resetting above the highest id does nothing
+	ResetEntitiesAfter(cid2)
+	assert.Equal(t, cid, HighestID())
+	// The reset slot is reassigned with a new generation
+	cid3 := ent{}.Init()
+	assert.Equal(t, cid2.Index(), cid3.Index())
+	assert.NotEqual(t, cid2, cid3)
+	assert.False(t, HasEntity(int(cid2)))
+}
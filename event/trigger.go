@@ -2,6 +2,7 @@ package event
 
 import (
 	"sync"
+	"time"
 )
 
 // TriggerBack is a version of Trigger which returns a channel that
@@ -19,11 +20,28 @@ import (
 //
 // TriggerBack is right now used by the primary logic loop to dictate logical
 // framerate, so EnterFrame events are called through TriggerBack.
+//
+// If the event has a registered payload type which data does not match, no
+// bindables are called but the returned channel is still sent to. If the bus
+// is synchronous, bindables are called as in TriggerSync before TriggerBack
+// returns.
 func (eb *Bus) TriggerBack(eventName string, data interface{}) chan bool {
 
+	valid := validTrigger(eventName, data)
+	if eb.Synchronous() {
+		// The channel is buffered as nothing is left to wait on
+		ch := make(chan bool, 1)
+		if valid {
+			eb.triggerSync(eventName, data, eb.orderedBindings(eventName, 0, false))
+		}
+		ch <- true
+		return ch
+	}
 	ch := make(chan bool)
 	go func(ch chan bool, eb *Bus, eventName string, data interface{}) {
-		eb.trigger(eventName, data)
+		if valid {
+			eb.trigger(eventName, data)
+		}
 		ch <- true
 	}(ch, eb, eventName, data)
 
@@ -31,14 +49,24 @@ func (eb *Bus) TriggerBack(eventName string, data interface{}) chan bool {
 }
 
 // Trigger will scan through the event bus and call all bindables found attached
-// to the given event, with the passed in data.
+// to the given event, with the passed in data. If the event has a registered
+// payload type which data does not match, the trigger is logged and dropped.
+// If the bus is synchronous, this is equivalent to TriggerSync.
 func (eb *Bus) Trigger(eventName string, data interface{}) {
+	if !validTrigger(eventName, data) {
+		return
+	}
+	if eb.Synchronous() {
+		eb.triggerSync(eventName, data, eb.orderedBindings(eventName, 0, false))
+		return
+	}
 	go func(eb *Bus, eventName string, data interface{}) {
 		eb.trigger(eventName, data)
 	}(eb, eventName, data)
 }
 
 func (eb *Bus) trigger(eventName string, data interface{}) {
+	eb.recordTrigger(eventName)
 	eb.mutex.RLock()
 	// Loop through all bindableStores for this eventName
 	for id, bs := range (*eb).bindingMap[eventName] {
@@ -46,14 +74,14 @@ func (eb *Bus) trigger(eventName string, data interface{}) {
 		for i := bs.highIndex - 1; i >= 0; i-- {
 			lst := bs.highPriority[i]
 			if lst != nil {
-				eb.triggerDefault((*lst).sl, id, eventName, data)
+				eb.triggerDefault((*lst).sl, id, i+1, eventName, data)
 			}
 		}
 	}
 
 	for id, bs := range (*eb).bindingMap[eventName] {
 		if bs != nil && bs.defaultPriority != nil {
-			eb.triggerDefault((bs.defaultPriority).sl, id, eventName, data)
+			eb.triggerDefault((bs.defaultPriority).sl, id, 0, eventName, data)
 		}
 	}
 
@@ -62,37 +90,45 @@ func (eb *Bus) trigger(eventName string, data interface{}) {
 		for i := 0; i < bs.lowIndex; i++ {
 			lst := bs.lowPriority[i]
 			if lst != nil {
-				eb.triggerDefault((*lst).sl, id, eventName, data)
+				eb.triggerDefault((*lst).sl, id, -(i + 1), eventName, data)
 			}
 		}
 	}
 	eb.mutex.RUnlock()
 }
 
-func (eb *Bus) triggerDefault(sl []Bindable, id int, eventName string, data interface{}) {
+func (eb *Bus) triggerDefault(sl []Bindable, id, priority int, eventName string, data interface{}) {
 	prog := &sync.WaitGroup{}
 	prog.Add(len(sl))
 	for i, bnd := range sl {
 		go func(bnd Bindable, id int, eventName string, data interface{}, prog *sync.WaitGroup, index int) {
-			eb.handleBindable(bnd, id, data, index, eventName)
+			eb.handleBindable(bnd, id, data, index, priority, eventName)
 			prog.Done()
 		}(bnd, id, eventName, data, prog, i)
 	}
 	prog.Wait()
 }
 
-func (eb *Bus) handleBindable(bnd Bindable, id int, data interface{}, index int, eventName string) {
+func (eb *Bus) handleBindable(bnd Bindable, id int, data interface{}, index, priority int, eventName string) {
 	if bnd != nil {
 		if id == 0 || GetEntity(id) != nil {
+			profiling := eb.Profiling()
+			var start time.Time
+			if profiling {
+				start = time.Now()
+			}
 			response := bnd(id, data)
+			if profiling {
+				eb.recordCall(eventName, time.Since(start), response)
+			}
 			switch response {
 			case UnbindEvent:
-				UnbindAll(BindingOption{
+				eb.UnbindAll(BindingOption{
 					Event{
 						eventName,
 						id,
 					},
-					0,
+					priority,
 				})
 			case UnbindSingle:
 				binding{
@@ -101,7 +137,7 @@ func (eb *Bus) handleBindable(bnd Bindable, id int, data interface{}, index int,
 							eventName,
 							id,
 						},
-						0,
+						priority,
 					},
 					index,
 				}.unbind(eb)
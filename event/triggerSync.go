@@ -0,0 +1,127 @@
+package event
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// SetSynchronous sets whether Trigger and TriggerBack dispatch events on the
+// bus as TriggerSync does, instead of calling each bindable in its own
+// goroutine. This also applies to CID.Trigger when set on the DefaultBus.
+func (eb *Bus) SetSynchronous(synchronous bool) {
+	var v int32
+	if synchronous {
+		v = 1
+	}
+	atomic.StoreInt32(&eb.synchronous, v)
+}
+
+// Synchronous returns whether the bus dispatches events synchronously.
+func (eb *Bus) Synchronous() bool {
+	return atomic.LoadInt32(&eb.synchronous) == 1
+}
+
+// TriggerSync calls every bindable bound to the given event on the calling
+// goroutine, returning once they have all returned. Bindables are called in
+// order of descending priority, then ascending caller id index, then the
+// order they were bound in.
+//
+// Unlike TriggerBack, the bus is not locked while bindables run, so it is
+// safe for a bindable to call TriggerSync itself. Bindings and unbindings
+// made while the event is dispatched take effect on the next flush, as they
+// would otherwise.
+func (eb *Bus) TriggerSync(eventName string, data interface{}) {
+	if !validTrigger(eventName, data) {
+		return
+	}
+	eb.triggerSync(eventName, data, eb.orderedBindings(eventName, 0, false))
+}
+
+// TriggerSync triggers an event for only this CID on the default bus, as
+// bus.TriggerSync would.
+func (cid CID) TriggerSync(eventName string, data interface{}) {
+	if !validTrigger(eventName, data) {
+		return
+	}
+	DefaultBus.triggerSync(eventName, data, DefaultBus.orderedBindings(eventName, int(cid), true))
+}
+
+// TriggerSync calls TriggerSync on the DefaultBus
+func TriggerSync(eventName string, data interface{}) {
+	DefaultBus.TriggerSync(eventName, data)
+}
+
+// An orderedBinding is a bindable copied out of a bus along with
+// what it needs to be ordered and unbound.
+type orderedBinding struct {
+	fn       Bindable
+	id       int
+	priority int
+	index    int
+	order    int64
+}
+
+// orderedBindings copies out the bindables bound to an event, optionally
+// only those for a single caller, in the order TriggerSync calls them.
+func (eb *Bus) orderedBindings(eventName string, callerID int, onlyCaller bool) []orderedBinding {
+	var bnds []orderedBinding
+	eb.mutex.RLock()
+	for id, bs := range eb.bindingMap[eventName] {
+		if bs == nil || (onlyCaller && id != callerID) {
+			continue
+		}
+		for i := 0; i < len(bs.highPriority); i++ {
+			bnds = bs.highPriority[i].appendOrdered(bnds, id, i+1)
+		}
+		bnds = bs.defaultPriority.appendOrdered(bnds, id, 0)
+		for i := 0; i < len(bs.lowPriority); i++ {
+			bnds = bs.lowPriority[i].appendOrdered(bnds, id, -(i + 1))
+		}
+	}
+	eb.mutex.RUnlock()
+
+	sort.Slice(bnds, func(i, j int) bool {
+		if bnds[i].priority != bnds[j].priority {
+			return bnds[i].priority > bnds[j].priority
+		}
+		if bnds[i].id != bnds[j].id {
+			return callerLess(bnds[i].id, bnds[j].id)
+		}
+		return bnds[i].order < bnds[j].order
+	})
+	return bnds
+}
+
+// callerLess orders caller ids by index, then by generation.
+func callerLess(id1, id2 int) bool {
+	cid1, cid2 := CID(id1), CID(id2)
+	if cid1.Index() != cid2.Index() {
+		return cid1.Index() < cid2.Index()
+	}
+	return cid1.Generation() < cid2.Generation()
+}
+
+func (bl *bindableList) appendOrdered(bnds []orderedBinding, id, priority int) []orderedBinding {
+	if bl == nil {
+		return bnds
+	}
+	for i, fn := range bl.sl {
+		if fn != nil {
+			bnds = append(bnds, orderedBinding{
+				fn:       fn,
+				id:       id,
+				priority: priority,
+				index:    i,
+				order:    bl.orders[i],
+			})
+		}
+	}
+	return bnds
+}
+
+func (eb *Bus) triggerSync(eventName string, data interface{}, bnds []orderedBinding) {
+	eb.recordTrigger(eventName)
+	for _, bnd := range bnds {
+		eb.handleBindable(bnd.fn, bnd.id, data, bnd.index, bnd.priority, eventName)
+	}
+}
@@ -0,0 +1,101 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerSyncOrder(t *testing.T) {
+	eb := NewBus()
+	var order []string
+	record := func(s string, response int) Bindable {
+		return func(int, interface{}) int {
+			order = append(order, s)
+			return response
+		}
+	}
+	// Bindables are only called for entities that exist
+	ResetEntities()
+	cid1 := ent{}.Init()
+	cid2 := ent{}.Init()
+
+	eb.Bind(record("2-default", 0), "S", int(cid2))
+	eb.Bind(record("1-default-a", 0), "S", int(cid1))
+	eb.BindPriority(record("2-low", 0), BindingOption{Event{"S", int(cid2)}, -1})
+	eb.BindPriority(record("1-lowest", 0), BindingOption{Event{"S", int(cid1)}, -2})
+	eb.BindPriority(record("2-high", UnbindSingle), BindingOption{Event{"S", int(cid2)}, 3})
+	eb.Bind(record("1-default-b", 0), "S", int(cid1))
+	eb.BindPriority(record("1-high", 0), BindingOption{Event{"S", int(cid1)}, 3})
+	eb.GlobalBind(record("0-default", 0), "S")
+	assert.Nil(t, eb.Flush())
+
+	expected := []string{
+		"1-high", "2-high",
+		"0-default", "1-default-a", "1-default-b", "2-default",
+		"2-low", "1-lowest",
+	}
+	for i := 0; i < 3; i++ {
+		order = nil
+		eb.TriggerSync("S", nil)
+		assert.Equal(t, expected, order)
+		if i == 0 {
+			// 2-high unbound itself
+			expected = append(expected[:1], expected[2:]...)
+		}
+		assert.Nil(t, eb.Flush())
+	}
+
+	order = nil
+	eb.SetSynchronous(true)
+	assert.True(t, eb.Synchronous())
+	eb.Trigger("S", nil)
+	assert.Equal(t, expected, order)
+	order = nil
+	<-eb.TriggerBack("S", nil)
+	assert.Equal(t, expected, order)
+}
+
+func TestTriggerSyncReentrant(t *testing.T) {
+	eb := NewBus()
+	eb.SetSynchronous(true)
+	inner := 0
+	eb.GlobalBind(func(int, interface{}) int {
+		eb.TriggerSync("Inner", nil)
+		<-eb.TriggerBack("Inner", nil)
+		eb.GlobalBind(func(int, interface{}) int {
+			return 0
+		}, "Outer")
+		return 0
+	}, "Outer")
+	eb.GlobalBind(func(int, interface{}) int {
+		inner++
+		return 0
+	}, "Inner")
+	assert.Nil(t, eb.Flush())
+	eb.TriggerSync("Outer", nil)
+	assert.Equal(t, 2, inner)
+	assert.Nil(t, eb.Flush())
+}
+
+func TestCIDTriggerSync(t *testing.T) {
+	triggers := 0
+	ResetEntities()
+	cid1 := ent{}.Init()
+	cid2 := ent{}.Init()
+	cid1.Bind(func(int, interface{}) int {
+		triggers++
+		return 0
+	}, "CIDSync")
+	cid2.Bind(func(int, interface{}) int {
+		triggers += 10
+		return 0
+	}, "CIDSync")
+	assert.Nil(t, Flush())
+	cid1.TriggerSync("CIDSync", nil)
+	assert.Equal(t, 1, triggers)
+	DefaultBus.SetSynchronous(true)
+	cid2.Trigger("CIDSync", nil)
+	DefaultBus.SetSynchronous(false)
+	assert.Equal(t, 11, triggers)
+}
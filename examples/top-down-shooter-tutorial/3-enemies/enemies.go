@@ -14,6 +14,7 @@ import (
 	"github.com/oakmound/oak/key"
 	"github.com/oakmound/oak/mouse"
 	"github.com/oakmound/oak/physics"
+	"github.com/oakmound/oak/physics/steering"
 	"github.com/oakmound/oak/render"
 	"github.com/oakmound/oak/scene"
 )
@@ -31,11 +32,15 @@ var (
 	// position so long as we don't reset
 	// the player's position vector
 	playerPos physics.Vector
+	// enemies steer away from each other
+	// as they chase the player
+	enemies *steering.Group
 )
 
 func main() {
 	oak.Add("tds", func(string, interface{}) {
 		playerAlive = true
+		enemies = steering.NewGroup()
 		char := entities.NewMoving(100, 100, 32, 32,
 			render.NewColorBox(32, 32, color.RGBA{0, 255, 0, 255}),
 			nil, 0, 0)
@@ -104,6 +109,9 @@ func main() {
 const (
 	EnemyRefresh = 30
 	EnemySpeed   = 2
+	// EnemyTurning is how quickly enemies can
+	// change their velocity
+	EnemyTurning = .25
 )
 
 func NewEnemy() {
@@ -117,19 +125,24 @@ func NewEnemy() {
 
 	enemy.UpdateLabel(Enemy)
 
+	agent := steering.NewAgent(enemy.Vector, EnemySpeed, EnemyTurning)
+	enemies.Add(agent)
+	behaviours := []steering.Weighted{
+		steering.Weight(steering.Seek{Target: playerPos}, 1),
+		steering.Weight(steering.Separation{Group: enemies, Radius: 24}, 1.5),
+	}
+
 	enemy.Bind(func(id int, _ interface{}) int {
 		enemy := event.GetEntity(id).(*entities.Solid)
 		// move towards the player
-		x, y := enemy.GetPos()
-		pt := floatgeom.Point2{x, y}
-		pt2 := floatgeom.Point2{playerPos.X(), playerPos.Y()}
-		delta := pt2.Sub(pt).Normalize().MulConst(EnemySpeed)
+		delta := agent.Update(behaviours...)
 		enemy.ShiftPos(delta.X(), delta.Y())
 		return 0
 	}, event.Enter)
 
 	enemy.Bind(func(id int, _ interface{}) int {
 		enemy := event.GetEntity(id).(*entities.Solid)
+		enemies.Remove(agent)
 		enemy.Destroy()
 		return 0
 	}, "Destroy")
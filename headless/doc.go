@@ -0,0 +1,5 @@
+// Package headless provides a screen driver for oak which does not open a
+// window, backing all of its images, textures and windows with in-memory
+// rgba buffers. It is intended for running oak in tests and other
+// environments without a display.
+package headless
@@ -0,0 +1,86 @@
+package headless
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/oakmound/shiny/screen"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mobile/event/size"
+)
+
+func TestMain(t *testing.T) {
+	var s screen.Screen
+	Main(func(s2 screen.Screen) {
+		s = s2
+	})
+	assert.Equal(t, DefaultScreen, s)
+}
+
+func TestEventDeque(t *testing.T) {
+	s := NewScreen()
+	assert.Nil(t, s.Window())
+	sw, err := s.NewWindow(screen.NewWindowGenerator(screen.Dimensions(4, 4)))
+	assert.Nil(t, err)
+	w := s.Window()
+	assert.Equal(t, sw, w)
+
+	w.Send(1)
+	w.Send(2)
+	w.SendFirst(0)
+	assert.Equal(t, 0, w.NextEvent())
+	assert.Equal(t, 1, w.NextEvent())
+	assert.Equal(t, 2, w.NextEvent())
+
+	got := make(chan interface{})
+	go func() {
+		got <- w.NextEvent()
+	}()
+	w.Send(3)
+	assert.Equal(t, 3, <-got)
+
+	w.Release()
+	w.Send(4)
+	w.Send(5)
+	select {
+	case <-got:
+		t.Fatal("Released window should not receive events")
+	default:
+	}
+}
+
+func TestWindowDrawing(t *testing.T) {
+	s := NewScreen()
+	sw, _ := s.NewWindow(screen.NewWindowGenerator(screen.Dimensions(4, 4)))
+	w := sw.(*Window)
+
+	img, err := s.NewImage(image.Point{2, 2})
+	assert.Nil(t, err)
+	assert.Equal(t, image.Point{2, 2}, img.Size())
+	draw.Draw(img.RGBA(), img.Bounds(), image.NewUniform(color.RGBA{255, 0, 0, 255}), image.Point{}, draw.Src)
+
+	tx, err := s.NewTexture(image.Point{2, 2})
+	assert.Nil(t, err)
+	assert.Equal(t, image.Point{2, 2}, tx.Size())
+	tx.Upload(image.Point{}, img, img.Bounds())
+
+	w.Scale(image.Rect(0, 0, 4, 4), tx, tx.Bounds(), draw.Src)
+	assert.Equal(t, color.RGBA{}, w.Published().RGBAAt(3, 3))
+	w.Publish()
+	assert.Equal(t, 1, w.Publishes())
+	assert.Equal(t, color.RGBA{255, 0, 0, 255}, w.Published().RGBAAt(3, 3))
+
+	w.Fill(image.Rect(0, 0, 1, 1), color.RGBA{0, 255, 0, 255}, draw.Src)
+	w.Copy(image.Point{3, 3}, tx, image.Rect(0, 0, 1, 1), draw.Src)
+	w.Publish()
+	assert.Equal(t, color.RGBA{0, 255, 0, 255}, w.Published().RGBAAt(0, 0))
+
+	w.Send(size.Event{WidthPx: 8, HeightPx: 6})
+	assert.Equal(t, image.Rect(0, 0, 8, 6), w.Published().Bounds())
+	w.Upload(image.Point{6, 4}, img, img.Bounds())
+	w.Publish()
+	assert.Equal(t, color.RGBA{255, 0, 0, 255}, w.Published().RGBAAt(7, 5))
+	assert.IsType(t, size.Event{}, w.NextEvent())
+}
@@ -0,0 +1,143 @@
+package headless
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+
+	"github.com/oakmound/shiny/screen"
+)
+
+var (
+	_ screen.Screen  = &Screen{}
+	_ screen.Image   = &Image{}
+	_ screen.Texture = &Texture{}
+)
+
+var (
+	// DefaultScreen is the screen passed in to functions by Main.
+	DefaultScreen = NewScreen()
+)
+
+// Main is a Driver which runs f against DefaultScreen, returning once f
+// returns.
+func Main(f func(screen.Screen)) {
+	f(DefaultScreen)
+}
+
+// A Screen is a screen.Screen which creates in-memory images, textures and
+// windows.
+type Screen struct {
+	windows []*Window
+	mutex   sync.Mutex
+}
+
+// NewScreen returns a Screen with no windows
+func NewScreen() *Screen {
+	return &Screen{
+		windows: make([]*Window, 0),
+	}
+}
+
+// NewImage returns a new rgba-backed image of the given size
+func (s *Screen) NewImage(size image.Point) (screen.Image, error) {
+	return &Image{
+		rgba: image.NewRGBA(image.Rectangle{Max: size}),
+	}, nil
+}
+
+// NewTexture returns a new rgba-backed texture of the given size
+func (s *Screen) NewTexture(size image.Point) (screen.Texture, error) {
+	return &Texture{
+		rgba: image.NewRGBA(image.Rectangle{Max: size}),
+	}, nil
+}
+
+// NewWindow returns a new Window with the dimensions described by opts.
+// The window will not be shown anywhere, but its published contents can be
+// read back through Published.
+func (s *Screen) NewWindow(opts screen.WindowGenerator) (screen.Window, error) {
+	w := newWindow(opts.Width, opts.Height)
+	s.mutex.Lock()
+	s.windows = append(s.windows, w)
+	s.mutex.Unlock()
+	return w, nil
+}
+
+// Window returns the most recently created window on this screen, or nil
+// if no windows have been created.
+func (s *Screen) Window() *Window {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.windows) == 0 {
+		return nil
+	}
+	return s.windows[len(s.windows)-1]
+}
+
+// An Image is a screen.Image backed by an *image.RGBA
+type Image struct {
+	rgba *image.RGBA
+}
+
+// Release does nothing, as the image's memory is managed by the garbage
+// collector.
+func (i *Image) Release() {}
+
+// Size returns the dimensions of the image
+func (i *Image) Size() image.Point {
+	return i.rgba.Rect.Max
+}
+
+// Bounds returns the bounds of the image
+func (i *Image) Bounds() image.Rectangle {
+	return i.rgba.Rect
+}
+
+// RGBA returns the underlying buffer of the image
+func (i *Image) RGBA() *image.RGBA {
+	return i.rgba
+}
+
+// A Texture is a screen.Texture backed by an *image.RGBA
+type Texture struct {
+	rgba *image.RGBA
+}
+
+// Release does nothing, as the texture's memory is managed by the garbage
+// collector.
+func (t *Texture) Release() {}
+
+// Size returns the dimensions of the texture
+func (t *Texture) Size() image.Point {
+	return t.rgba.Rect.Max
+}
+
+// Bounds returns the bounds of the texture
+func (t *Texture) Bounds() image.Rectangle {
+	return t.rgba.Rect
+}
+
+// Upload copies the sr portion of src to the texture at dp
+func (t *Texture) Upload(dp image.Point, src screen.Image, sr image.Rectangle) {
+	upload(t.rgba, dp, src, sr)
+}
+
+// Fill fills the dr portion of the texture with src
+func (t *Texture) Fill(dr image.Rectangle, src color.Color, op draw.Op) {
+	fill(t.rgba, dr, src, op)
+}
+
+// RGBA returns the underlying buffer of the texture
+func (t *Texture) RGBA() *image.RGBA {
+	return t.rgba
+}
+
+func upload(dst *image.RGBA, dp image.Point, src screen.Image, sr image.Rectangle) {
+	draw.Draw(dst, sr.Sub(sr.Min).Add(dp), src.RGBA(), sr.Min, draw.Src)
+}
+
+func fill(dst *image.RGBA, dr image.Rectangle, src color.Color, op draw.Op) {
+	draw.Draw(dst, dr, image.NewUniform(src), image.Point{}, op)
+}
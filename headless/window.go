@@ -0,0 +1,176 @@
+package headless
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+
+	"github.com/oakmound/shiny/screen"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/mobile/event/size"
+)
+
+var (
+	_ screen.Window = &Window{}
+)
+
+// A Window is a screen.Window which draws to an in-memory back buffer and
+// copies that buffer when published. Events can be pushed into a Window
+// through Send and SendFirst as if they came from the operating system.
+type Window struct {
+	back      *image.RGBA
+	published *image.RGBA
+	publishes int
+	drawMutex sync.Mutex
+
+	events    []interface{}
+	eventCond *sync.Cond
+	released  bool
+}
+
+func newWindow(width, height int) *Window {
+	bds := image.Rect(0, 0, width, height)
+	return &Window{
+		back:      image.NewRGBA(bds),
+		published: image.NewRGBA(bds),
+		events:    make([]interface{}, 0),
+		eventCond: sync.NewCond(&sync.Mutex{}),
+	}
+}
+
+// Release marks the window as released. Events sent to a released window
+// are dropped.
+func (w *Window) Release() {
+	w.eventCond.L.Lock()
+	w.released = true
+	w.eventCond.L.Unlock()
+}
+
+// Send adds an event to the end of the window's event queue. Sending a
+// size.Event will resize the window's buffers, as an operating system would
+// before notifying the window of its new size.
+func (w *Window) Send(event interface{}) {
+	w.resizeTo(event)
+	w.eventCond.L.Lock()
+	if !w.released {
+		w.events = append(w.events, event)
+		w.eventCond.Signal()
+	}
+	w.eventCond.L.Unlock()
+}
+
+// SendFirst adds an event to the front of the window's event queue
+func (w *Window) SendFirst(event interface{}) {
+	w.resizeTo(event)
+	w.eventCond.L.Lock()
+	if !w.released {
+		w.events = append([]interface{}{event}, w.events...)
+		w.eventCond.Signal()
+	}
+	w.eventCond.L.Unlock()
+}
+
+func (w *Window) resizeTo(event interface{}) {
+	e, ok := event.(size.Event)
+	if !ok {
+		return
+	}
+	bds := image.Rect(0, 0, e.WidthPx, e.HeightPx)
+	w.drawMutex.Lock()
+	w.back = image.NewRGBA(bds)
+	w.published = image.NewRGBA(bds)
+	w.drawMutex.Unlock()
+}
+
+// NextEvent returns the next event in the window's event queue, blocking
+// until one is available.
+func (w *Window) NextEvent() interface{} {
+	w.eventCond.L.Lock()
+	for len(w.events) == 0 {
+		w.eventCond.Wait()
+	}
+	e := w.events[0]
+	w.events = w.events[1:]
+	w.eventCond.L.Unlock()
+	return e
+}
+
+// Upload copies the sr portion of src to the window's back buffer at dp
+func (w *Window) Upload(dp image.Point, src screen.Image, sr image.Rectangle) {
+	w.drawMutex.Lock()
+	upload(w.back, dp, src, sr)
+	w.drawMutex.Unlock()
+}
+
+// Fill fills the dr portion of the window's back buffer with src
+func (w *Window) Fill(dr image.Rectangle, src color.Color, op draw.Op) {
+	w.drawMutex.Lock()
+	fill(w.back, dr, src, op)
+	w.drawMutex.Unlock()
+}
+
+// Draw draws the sr portion of src to the window's back buffer, transformed
+// by src2dst
+func (w *Window) Draw(src2dst f64.Aff3, src screen.Texture, sr image.Rectangle, op draw.Op) {
+	w.drawMutex.Lock()
+	xdraw.NearestNeighbor.Transform(w.back, src2dst, textureRGBA(src), sr, op, nil)
+	w.drawMutex.Unlock()
+}
+
+// DrawUniform draws the uniform color src to the sr portion of the window's
+// back buffer, transformed by src2dst
+func (w *Window) DrawUniform(src2dst f64.Aff3, src color.Color, sr image.Rectangle, op draw.Op) {
+	w.drawMutex.Lock()
+	xdraw.NearestNeighbor.Transform(w.back, src2dst, image.NewUniform(src), sr, op, nil)
+	w.drawMutex.Unlock()
+}
+
+// Copy copies the sr portion of src to the window's back buffer at dp
+func (w *Window) Copy(dp image.Point, src screen.Texture, sr image.Rectangle, op draw.Op) {
+	w.drawMutex.Lock()
+	draw.Draw(w.back, sr.Sub(sr.Min).Add(dp), textureRGBA(src), sr.Min, op)
+	w.drawMutex.Unlock()
+}
+
+// Scale scales the sr portion of src to fill dr on the window's back buffer
+func (w *Window) Scale(dr image.Rectangle, src screen.Texture, sr image.Rectangle, op draw.Op) {
+	w.drawMutex.Lock()
+	xdraw.NearestNeighbor.Scale(w.back, dr, textureRGBA(src), sr, op, nil)
+	w.drawMutex.Unlock()
+}
+
+// Publish copies the window's back buffer to its published buffer
+func (w *Window) Publish() screen.PublishResult {
+	w.drawMutex.Lock()
+	copy(w.published.Pix, w.back.Pix)
+	w.publishes++
+	w.drawMutex.Unlock()
+	return screen.PublishResult{BackBufferPreserved: true}
+}
+
+// Published returns a copy of what was most recently published to the window
+func (w *Window) Published() *image.RGBA {
+	w.drawMutex.Lock()
+	out := image.NewRGBA(w.published.Rect)
+	copy(out.Pix, w.published.Pix)
+	w.drawMutex.Unlock()
+	return out
+}
+
+// Publishes returns how many times this window has been published to
+func (w *Window) Publishes() int {
+	w.drawMutex.Lock()
+	defer w.drawMutex.Unlock()
+	return w.publishes
+}
+
+// textureRGBA returns the rgba buffer behind a texture. Textures which were
+// not created by a headless Screen are drawn as empty.
+func textureRGBA(t screen.Texture) *image.RGBA {
+	if ht, ok := t.(*Texture); ok {
+		return ht.rgba
+	}
+	return image.NewRGBA(t.Bounds())
+}
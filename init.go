@@ -47,6 +47,21 @@ var (
 	// viewport positions should be drawn
 	viewportCh = make(chan [2]int)
 
+	// The step channel receives a signal when
+	// a logical frame should be stepped through.
+	stepCh = make(chan bool)
+
+	// The step done channel receives a signal once
+	// a frame requested on the step channel is done.
+	// It is separate from the step channel so that
+	// concurrent steps cannot take each other's signals.
+	stepDoneCh = make(chan bool)
+
+	// The draw step channel receives a signal when
+	// a frame should be drawn while frame stepping,
+	// and sends one back once that frame is published.
+	drawStepCh = make(chan bool)
+
 	debugResetInProgress bool
 
 	// ScreenWidth is the width of the screen
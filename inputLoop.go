@@ -48,16 +48,8 @@ func inputLoop() {
 		// The specific key that is pressed is passed as the data interface for
 		// the former events, but not for the latter.
 		case key.Event:
-			// key.Code strings all begin with "Code". This strips that off.
-			k := GetKeyBind(e.Code.String()[4:])
-			if e.Direction == key.DirPress {
-				setDown(k)
-				logicHandler.Trigger(okey.Down, k)
-				logicHandler.Trigger(okey.Down+k, nil)
-			} else if e.Direction == key.DirRelease {
-				setUp(k)
-				logicHandler.Trigger(okey.Up, k)
-				logicHandler.Trigger(okey.Up+k, nil)
+			if !ReplayingInput() {
+				triggerKeyEvent(e)
 			}
 
 		// Send mouse events
@@ -74,34 +66,23 @@ func inputLoop() {
 		//
 		// Mouse events all receive an x, y, and button string.
 		case mouse.Event:
-			button := omouse.GetMouseButton(e.Button)
-			eventName := omouse.GetEventName(e.Direction, e.Button)
-			if e.Direction == mouse.DirPress {
-				setDown(button)
-			} else if e.Direction == mouse.DirRelease {
-				setUp(button)
+			if !ReplayingInput() {
+				// The event triggered for mouse events has the same scaling as the
+				// render and collision space. I.e. if the viewport is at 0, the mouse's
+				// position is exactly the same as the position of a visible entity
+				// on screen. When not at zero, the offset will be exactly the viewport.
+				// Todo: consider incorporating viewport into the event, see the
+				// workaround needed in mouseDetails, and how mouse events might not
+				// propagate to their expected position.
+				e.X = ((e.X - float32(windowRect.Min.X)) / float32(windowRect.Max.X-windowRect.Min.X)) * float32(ScreenWidth)
+				e.Y = ((e.Y - float32(windowRect.Min.Y)) / float32(windowRect.Max.Y-windowRect.Min.Y)) * float32(ScreenHeight)
+				triggerMouseEvent(e)
 			}
-			// The event triggered for mouse events has the same scaling as the
-			// render and collision space. I.e. if the viewport is at 0, the mouse's
-			// position is exactly the same as the position of a visible entity
-			// on screen. When not at zero, the offset will be exactly the viewport.
-			// Todo: consider incorporating viewport into the event, see the
-			// workaround needed in mouseDetails, and how mouse events might not
-			// propagate to their expected position.
-			mevent := omouse.NewEvent(
-				float64((((e.X - float32(windowRect.Min.X)) / float32(windowRect.Max.X-windowRect.Min.X)) * float32(ScreenWidth))),
-				float64((((e.Y - float32(windowRect.Min.Y)) / float32(windowRect.Max.Y-windowRect.Min.Y)) * float32(ScreenHeight))),
-				button,
-				eventName,
-			)
-
-			omouse.Propagate(eventName+"On", mevent)
-			logicHandler.Trigger(eventName, mevent)
 
 		case gesture.Event:
-			eventName := "Gesture" + e.Type.String()
-			dlog.Verb(eventName)
-			logicHandler.Trigger(eventName, omouse.FromShinyGesture(e))
+			if !ReplayingInput() {
+				triggerGestureEvent(e)
+			}
 
 		// There's something called a paint event that we don't respond to
 
@@ -120,3 +101,47 @@ func inputLoop() {
 		}
 	}
 }
+
+// triggerKeyEvent sends a key event to the logic handler,
+// recording it if input is being recorded.
+func triggerKeyEvent(e key.Event) {
+	recordInput(inputRecord{Key: &e})
+	// key.Code strings all begin with "Code". This strips that off.
+	k := GetKeyBind(e.Code.String()[4:])
+	if e.Direction == key.DirPress {
+		setDown(k)
+		logicHandler.Trigger(okey.Down, k)
+		logicHandler.Trigger(okey.Down+k, nil)
+	} else if e.Direction == key.DirRelease {
+		setUp(k)
+		logicHandler.Trigger(okey.Up, k)
+		logicHandler.Trigger(okey.Up+k, nil)
+	}
+}
+
+// triggerMouseEvent sends a mouse event, already scaled to the
+// screen, to the logic handler and mouse collision tree, recording
+// it if input is being recorded.
+func triggerMouseEvent(e mouse.Event) {
+	recordInput(inputRecord{Mouse: &e})
+	button := omouse.GetMouseButton(e.Button)
+	eventName := omouse.GetEventName(e.Direction, e.Button)
+	if e.Direction == mouse.DirPress {
+		setDown(button)
+	} else if e.Direction == mouse.DirRelease {
+		setUp(button)
+	}
+	mevent := omouse.NewEvent(float64(e.X), float64(e.Y), button, eventName)
+
+	omouse.Propagate(eventName+"On", mevent)
+	logicHandler.Trigger(eventName, mevent)
+}
+
+// triggerGestureEvent sends a gesture event to the logic handler,
+// recording it if input is being recorded.
+func triggerGestureEvent(e gesture.Event) {
+	recordInput(inputRecord{Gesture: &e})
+	eventName := "Gesture" + e.Type.String()
+	dlog.Verb(eventName)
+	logicHandler.Trigger(eventName, omouse.FromShinyGesture(e))
+}
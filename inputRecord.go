@@ -0,0 +1,174 @@
+package oak
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/oakmound/oak/dlog"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/shiny/gesture"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+const (
+	// InputRecordingVersion is the version of the input recording format
+	// written by RecordInput. ReplayInput will only accept recordings of
+	// this version.
+	InputRecordingVersion = 1
+)
+
+var (
+	// scenesStarted counts scenes started since Init, so recordings can
+	// tell apart frames from different scenes. It is written by the scene
+	// loop and read from other goroutines, so it is only accessed atomically.
+	scenesStarted int64
+
+	inputRecorder    *json.Encoder
+	recordStartScene int64
+	recordLock       sync.Mutex
+
+	replayRecords    []inputRecord
+	replayStartScene int64
+	replaying        bool
+	replayLock       sync.Mutex
+)
+
+type inputRecordingHeader struct {
+	Version int `json:"version"`
+}
+
+// An inputRecord is a single input event, stored with the scene and frame
+// it was triggered on. Scenes are counted from when recording began, frames
+// from when their scene began. Mouse events are stored in screen space,
+// after window scaling has been applied.
+type inputRecord struct {
+	Scene   int            `json:"scene"`
+	Frame   int            `json:"frame"`
+	Key     *key.Event     `json:"key,omitempty"`
+	Mouse   *mouse.Event   `json:"mouse,omitempty"`
+	Gesture *gesture.Event `json:"gesture,omitempty"`
+}
+
+// RecordInput begins recording all key, mouse and gesture events that oak
+// triggers to w, replacing any ongoing recording. Each event is tagged with
+// the logical frame it occurred on. Alongside SeedRNG, a recording can be
+// played back with ReplayInput to reproduce a play session.
+func RecordInput(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	err := enc.Encode(inputRecordingHeader{InputRecordingVersion})
+	if err != nil {
+		return err
+	}
+	recordLock.Lock()
+	inputRecorder = enc
+	recordStartScene = atomic.LoadInt64(&scenesStarted)
+	recordLock.Unlock()
+	return nil
+}
+
+// StopRecordingInput stops any ongoing input recording.
+func StopRecordingInput() {
+	recordLock.Lock()
+	inputRecorder = nil
+	recordLock.Unlock()
+}
+
+func recordInput(rec inputRecord) {
+	recordLock.Lock()
+	if inputRecorder != nil {
+		rec.Scene = int(atomic.LoadInt64(&scenesStarted) - recordStartScene)
+		rec.Frame = logicHandler.FramesElapsed()
+		dlog.ErrorCheck(inputRecorder.Encode(rec))
+	}
+	recordLock.Unlock()
+}
+
+// ReplayInput reads an input recording written by RecordInput from r, then
+// triggers its events on the frames they were recorded on in place of live
+// input. Live key, mouse and gesture events are ignored until the replay
+// finishes. For a replay to match its recording, it should begin at the same
+// point its recording began, e.g. before Init is called.
+func ReplayInput(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	var header inputRecordingHeader
+	err := dec.Decode(&header)
+	if err != nil {
+		return err
+	}
+	if header.Version != InputRecordingVersion {
+		return oakerr.UnsupportedFormat{
+			Format: "input recording version " + strconv.Itoa(header.Version),
+		}
+	}
+	records := []inputRecord{}
+	for {
+		var rec inputRecord
+		err = dec.Decode(&rec)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		records = append(records, rec)
+	}
+	replayLock.Lock()
+	replayRecords = records
+	replayStartScene = atomic.LoadInt64(&scenesStarted)
+	replaying = len(records) > 0
+	replayLock.Unlock()
+	return nil
+}
+
+// StopReplayingInput ends any ongoing input replay, returning control to
+// live input.
+func StopReplayingInput() {
+	replayLock.Lock()
+	replayRecords = nil
+	replaying = false
+	replayLock.Unlock()
+}
+
+// ReplayingInput returns whether an input replay is ongoing.
+func ReplayingInput() bool {
+	replayLock.Lock()
+	defer replayLock.Unlock()
+	return replaying
+}
+
+// replayFrame triggers all replayed events that were recorded on or before
+// the current frame.
+func replayFrame() {
+	replayLock.Lock()
+	if !replaying {
+		replayLock.Unlock()
+		return
+	}
+	scene := int(atomic.LoadInt64(&scenesStarted) - replayStartScene)
+	frame := logicHandler.FramesElapsed()
+	i := 0
+	for ; i < len(replayRecords); i++ {
+		rec := replayRecords[i]
+		if rec.Scene > scene || (rec.Scene == scene && rec.Frame > frame) {
+			break
+		}
+	}
+	toTrigger := replayRecords[:i]
+	replayRecords = replayRecords[i:]
+	replaying = len(replayRecords) > 0
+	replayLock.Unlock()
+
+	for _, rec := range toTrigger {
+		switch {
+		case rec.Key != nil:
+			triggerKeyEvent(*rec.Key)
+		case rec.Mouse != nil:
+			triggerMouseEvent(*rec.Mouse)
+		case rec.Gesture != nil:
+			triggerGestureEvent(*rec.Gesture)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package oak
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/headless"
+	okey "github.com/oakmound/oak/key"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+func TestRecordAndReplayInput(t *testing.T) {
+	resetOak()
+	testinit()
+	w := headless.DefaultScreen.Window()
+
+	buff := new(bytes.Buffer)
+	assert.Nil(t, RecordInput(buff))
+	w.Send(key.Event{Code: key.CodeB, Direction: key.DirPress})
+	w.Send(mouse.Event{X: 1, Y: 1, Button: mouse.ButtonLeft, Direction: mouse.DirPress})
+	sleep()
+	w.Send(key.Event{Code: key.CodeB, Direction: key.DirRelease})
+	sleep()
+	StopRecordingInput()
+	w.Send(key.Event{Code: key.CodeC, Direction: key.DirPress})
+	sleep()
+	assert.True(t, IsDown("C"))
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	assert.Equal(t, 4, len(lines))
+	var rec inputRecord
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &rec))
+	assert.Equal(t, key.CodeB, rec.Key.Code)
+	assert.Nil(t, json.Unmarshal([]byte(lines[2]), &rec))
+	assert.Equal(t, mouse.ButtonLeft, rec.Mouse.Button)
+	assert.Nil(t, json.Unmarshal([]byte(lines[3]), &rec))
+	assert.True(t, rec.Frame > 0)
+
+	setUp("LeftMouse")
+	presses := 0
+	event.GlobalBind(func(int, interface{}) int {
+		presses++
+		return 0
+	}, okey.Down+"B")
+	sleep()
+
+	assert.Nil(t, ReplayInput(bytes.NewBufferString(buff.String())))
+	assert.True(t, ReplayingInput())
+	// Live input is ignored during replays
+	w.Send(key.Event{Code: key.CodeC, Direction: key.DirRelease})
+	sleep()
+	assert.True(t, IsDown("C"))
+	setUp("C")
+	// The replay began at a later frame than the recording ended,
+	// so its events will all have been triggered on the next frame
+	assert.False(t, ReplayingInput())
+	assert.Equal(t, 1, presses)
+	assert.False(t, IsDown("B"))
+	assert.True(t, IsDown("LeftMouse"))
+	setUp("LeftMouse")
+
+	// Events are not triggered before their frame
+	late := inputRecord{Frame: logicHandler.FramesElapsed() + 100000, Key: &key.Event{Code: key.CodeB, Direction: key.DirPress}}
+	lateBuff := new(bytes.Buffer)
+	enc := json.NewEncoder(lateBuff)
+	assert.Nil(t, enc.Encode(inputRecordingHeader{InputRecordingVersion}))
+	assert.Nil(t, enc.Encode(late))
+	assert.Nil(t, ReplayInput(lateBuff))
+	sleep()
+	assert.True(t, ReplayingInput())
+	assert.Equal(t, 1, presses)
+	StopReplayingInput()
+	assert.False(t, ReplayingInput())
+
+	err := ReplayInput(bytes.NewBufferString(`{"version":0}`))
+	assert.IsType(t, oakerr.UnsupportedFormat{}, err)
+	err = ReplayInput(bytes.NewBufferString(`{"version":1}` + "\n{"))
+	assert.NotNil(t, err)
+	assert.False(t, ReplayingInput())
+}
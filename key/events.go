@@ -1,7 +1,15 @@
 package key
 
+import "github.com/oakmound/oak/event"
+
 // This lists event names used by oak for key input events.
+// Payload: (string) the key pressed or released
 const (
 	Down = "KeyDown"
 	Up   = "KeyUp"
 )
+
+func init() {
+	event.RegisterPayload(Down, "")
+	event.RegisterPayload(Up, "")
+}
@@ -44,6 +44,11 @@ const (
 	Stop  = "MouseCollisionStop"
 )
 
+func init() {
+	event.RegisterPayload(Start, Event{})
+	event.RegisterPayload(Stop, Event{})
+}
+
 func phaseCollisionEnter(id int, nothing interface{}) int {
 	e := event.GetEntity(id).(collisionPhase)
 	oc := e.getCollisionPhase()
@@ -1,5 +1,7 @@
 package mouse
 
+import "github.com/oakmound/oak/event"
+
 // Mouse events: MousePress, MouseRelease, MouseScrollDown, MouseScrollUp, MouseDrag
 // Payload: (mouse.Event) details of the mouse event
 const (
@@ -17,3 +19,12 @@ const (
 	ClickOn      = Click + "On"
 	DragOn       = Drag + "On"
 )
+
+func init() {
+	for _, name := range []string{
+		Press, Release, ScrollDown, ScrollUp, Click, Drag,
+		PressOn, ReleaseOn, ScrollDownOn, ScrollUpOn, ClickOn, DragOn,
+	} {
+		event.RegisterPayload(name, Event{})
+	}
+}
@@ -129,3 +129,27 @@ type UnsupportedPlatform struct {
 func (up UnsupportedPlatform) Error() string {
 	return up.Operation + " is not supported on this platform/OS"
 }
+
+// InvalidPayload is returned when an event is triggered with, or bound to
+// a handler expecting, a payload of a type other than the one registered
+// for that event.
+type InvalidPayload struct {
+	EventName string
+	Expected  string
+	Received  string
+}
+
+func (ip InvalidPayload) Error() string {
+	return "Invalid payload for event " + ip.EventName +
+		": expected " + ip.Expected + ", received " + ip.Received
+}
+
+// CyclicInput is returned when some input would make a structure which must
+// not have cycles, such as a hierarchy, depend on itself.
+type CyclicInput struct {
+	InputName string
+}
+
+func (ci CyclicInput) Error() string {
+	return "Input " + ci.InputName + " would create a cycle"
+}
@@ -32,5 +32,9 @@ func TestErrorsAreErrors(t *testing.T) {
 	assert.NotEmpty(t, err.Error())
 	err = UnsupportedPlatform{}
 	assert.NotEmpty(t, err.Error())
+	err = InvalidPayload{}
+	assert.NotEmpty(t, err.Error())
+	err = CyclicInput{}
+	assert.NotEmpty(t, err.Error())
 	// Assert nothing crashed
 }
@@ -0,0 +1,119 @@
+package oak
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/render"
+	"github.com/oakmound/oak/scene"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScenePersist(t *testing.T) {
+	resetOak()
+	testinit()
+
+	var playerFrames int32
+	var player event.CID
+	playerSpace := collision.NewUnassignedSpace(-200, -200, 10, 10)
+	var firstStack *render.DrawStack
+	AddScene("persistFirst", scene.Scene{
+		Start: func(string, interface{}) {
+			player = stackEnt{}.Init()
+			player.Bind(func(int, interface{}) int {
+				atomic.AddInt32(&playerFrames, 1)
+				return 0
+			}, event.Enter)
+			collision.Add(playerSpace)
+			render.Draw(render.EmptyRenderable())
+			firstStack = render.GlobalDrawStack
+		},
+		Loop: func() bool { return true },
+		Leave: func() scene.Exit {
+			return scene.Exit{
+				Persist: scene.Persist{
+					Bindings:  true,
+					Entities:  true,
+					Collision: true,
+					DrawStack: true,
+				},
+			}
+		},
+		End: func() (string, *scene.Result) {
+			return "persistSecond", nil
+		},
+	})
+	var next event.CID
+	AddScene("persistSecond", scene.Scene{
+		Start: func(string, interface{}) {
+			next = stackEnt{}.Init()
+		},
+		Loop: func() bool { return true },
+		End: func() (string, *scene.Result) {
+			return "blank", nil
+		},
+	})
+
+	testNextScene <- "persistFirst"
+	skipSceneCh <- true
+	sleep()
+	skipSceneCh <- true
+	sleep()
+	assert.Equal(t, "persistSecond", SceneMap.CurrentScene)
+	assert.NotNil(t, player.E())
+	assert.True(t, next.Index() > player.Index())
+	frames := atomic.LoadInt32(&playerFrames)
+	sleep()
+	assert.True(t, atomic.LoadInt32(&playerFrames) > frames)
+	// Frames are counted from the start of the new scene
+	assert.True(t, logicHandler.FramesElapsed() < int(atomic.LoadInt32(&playerFrames)))
+	assert.Equal(t, []*collision.Space{playerSpace}, collision.Hits(collision.NewUnassignedSpace(-195, -195, 1, 1)))
+	assert.True(t, firstStack == render.GlobalDrawStack)
+
+	skipSceneCh <- true
+	sleep()
+	assert.Nil(t, player.E())
+	assert.Empty(t, collision.Hits(collision.NewUnassignedSpace(-195, -195, 1, 1)))
+	assert.False(t, firstStack == render.GlobalDrawStack)
+}
+
+func TestSceneEndCarriesOver(t *testing.T) {
+	resetOak()
+	testinit()
+
+	var ended event.CID
+	endSpace := collision.NewUnassignedSpace(-300, -300, 10, 10)
+	AddScene("endFirst", scene.Scene{
+		Start: func(string, interface{}) {},
+		Loop:  func() bool { return true },
+		End: func() (string, *scene.Result) {
+			// The engine has already been reset, so what is created here
+			// belongs to the next scene
+			ended = stackEnt{}.Init()
+			collision.Add(endSpace)
+			return "endSecond", nil
+		},
+	})
+	AddScene("endSecond", scene.Scene{
+		Start: func(string, interface{}) {},
+		Loop:  func() bool { return true },
+		End: func() (string, *scene.Result) {
+			return "blank", nil
+		},
+	})
+
+	testNextScene <- "endFirst"
+	skipSceneCh <- true
+	sleep()
+	skipSceneCh <- true
+	sleep()
+	assert.Equal(t, "endSecond", SceneMap.CurrentScene)
+	assert.NotNil(t, ended.E())
+	assert.Equal(t, []*collision.Space{endSpace}, collision.Hits(collision.NewUnassignedSpace(-295, -295, 1, 1)))
+
+	skipSceneCh <- true
+	sleep()
+	assert.Nil(t, ended.E())
+}
@@ -0,0 +1,118 @@
+package rigid
+
+import (
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+)
+
+// A BodyType is how a World moves a body.
+type BodyType int
+
+// Body types
+const (
+	// Dynamic bodies are moved by their velocity, gravity, forces and
+	// contacts.
+	Dynamic BodyType = iota
+	// Kinematic bodies are only moved by their velocity, and push dynamic
+	// bodies out of their way as if they had infinite mass.
+	Kinematic
+	// Static bodies never move.
+	Static
+)
+
+// A Body is a collision space which a World moves. The position of a body
+// is the top left corner of its space, and is kept in its Vector each
+// frame, so renderables can be attached to a body as they would be to any
+// other vector. To move a body without its world, move its space within
+// the world's tree and wake it.
+type Body struct {
+	physics.Vector
+	physics.Mass
+	Space *collision.Space
+	// Velocity is how far the body moves each frame.
+	Velocity physics.Vector
+	// Restitution is how much of its speed the body keeps when it bounces,
+	// from 0 to 1. The greater restitution of two bodies is used when they
+	// touch.
+	Restitution float64
+	// Friction is how much the body resists sliding along what it touches.
+	// The geometric mean of the friction of two bodies is used when they
+	// touch, or the friction of the moving body alone when it touches a
+	// space which is not a body.
+	Friction float64
+	// GravityScale scales the gravity of the body's world for the body.
+	GravityScale float64
+	Type         BodyType
+
+	force    physics.Vector
+	sleeping bool
+	// still is how many steps the body has been moving slower than its
+	// world's SleepSpeed
+	still int
+}
+
+// NewBody returns a dynamic body for the given space with the given mass,
+// a friction of .2 and a gravity scale of 1. Mass must be positive.
+func NewBody(sp *collision.Space, mass float64) (*Body, error) {
+	if sp == nil {
+		return nil, oakerr.NilInput{InputName: "sp"}
+	}
+	b := &Body{
+		Vector:       physics.NewVector(sp.X(), sp.Y()),
+		Space:        sp,
+		Velocity:     physics.NewVector(0, 0),
+		Friction:     .2,
+		GravityScale: 1,
+		force:        physics.NewVector(0, 0),
+	}
+	if err := b.SetMass(mass); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GetDelta returns a body's velocity. With GetMass, this lets bodies be
+// pushed by physics.Push.
+func (b *Body) GetDelta() physics.Vector {
+	return b.Velocity
+}
+
+// ApplyForce adds a force to the body for the next frame, waking it.
+// Forces are divided by the body's mass and added to its velocity over the
+// course of the frame.
+func (b *Body) ApplyForce(f physics.Vector) {
+	b.force.Add(f)
+	b.Wake()
+}
+
+// ApplyImpulse immediately changes the body's velocity by the impulse
+// divided by its mass, waking it.
+func (b *Body) ApplyImpulse(j physics.Vector) {
+	if im := b.invMass(); im != 0 {
+		b.Velocity.Add(j.Copy().Scale(im))
+	}
+	b.Wake()
+}
+
+// Wake wakes a sleeping body.
+func (b *Body) Wake() {
+	b.sleeping = false
+	b.still = 0
+}
+
+// Sleeping returns whether the body is asleep. Sleeping bodies are not
+// moved until they are woken, either by Wake, by having a force or impulse
+// applied to them or by being hit.
+func (b *Body) Sleeping() bool {
+	return b.sleeping
+}
+
+// invMass returns the inverse of the body's mass, or 0 if it cannot be
+// moved by contacts.
+func (b *Body) invMass() float64 {
+	if b.Type != Dynamic || b.sleeping || b.GetMass() <= 0 {
+		return 0
+	}
+	return 1 / b.GetMass()
+}
@@ -0,0 +1,7 @@
+// Package rigid provides a world of rigid bodies, which fall, bounce and
+// slide against each other and against the spaces of a collision tree, and
+// which can be held together by joints.
+//
+// It is separate from package physics because package collision depends
+// on package physics.
+package rigid
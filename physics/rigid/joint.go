@@ -0,0 +1,359 @@
+package rigid
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/alg"
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+)
+
+// JointBreak: when a joint in a world is broken by a force greater than its
+// BreakForce. JointBreak is triggered globally after the world has stepped.
+// Payload: (BreakEvent) the broken joint and the force that broke it
+const JointBreak = "JointBreak"
+
+func init() {
+	event.RegisterPayload(JointBreak, BreakEvent{})
+}
+
+// A BreakEvent is the payload of JointBreak events.
+type BreakEvent struct {
+	Joint Joint
+	Force float64
+}
+
+// baumgarte is the fraction of a joint's error that it tries to correct
+// each step, per "Stabilization of constraints and integrals of motion in
+// dynamical systems" by J. Baumgarte, 1972
+const baumgarte = .2
+
+// Jointed is implemented by anything with a position that can be pushed,
+// and so can be held by joints. Joints only change the delta of what they
+// hold, so things which are not bodies of a world still need to be moved
+// by their deltas each frame.
+type Jointed interface {
+	physics.Pushable
+	Vec() physics.Vector
+}
+
+// An Anchor is a point held by a joint. If Body is nil, Offset is a fixed
+// point in the world. Otherwise, Offset is relative to Body's position.
+type Anchor struct {
+	Body   Jointed
+	Offset floatgeom.Point2
+}
+
+func (an Anchor) pos() floatgeom.Point2 {
+	switch b := an.Body.(type) {
+	case nil:
+		return an.Offset
+	case *Body:
+		// Bodies are moved during steps without updating their vectors
+		return an.Offset.Add(floatgeom.Point2{b.Space.X(), b.Space.Y()})
+	default:
+		v := b.Vec()
+		return an.Offset.Add(floatgeom.Point2{v.X(), v.Y()})
+	}
+}
+
+func (an Anchor) vel() floatgeom.Point2 {
+	if an.Body == nil {
+		return floatgeom.Point2{}
+	}
+	d := an.Body.GetDelta()
+	return floatgeom.Point2{d.X(), d.Y()}
+}
+
+func (an Anchor) invMass() float64 {
+	switch b := an.Body.(type) {
+	case nil:
+		return 0
+	case *Body:
+		return b.invMass()
+	default:
+		if m := b.GetMass(); m > 0 {
+			return 1 / m
+		}
+		return 0
+	}
+}
+
+func (an Anchor) applyImpulse(j floatgeom.Point2) {
+	if im := an.invMass(); im != 0 {
+		d := an.Body.GetDelta()
+		d.SetPos(d.X()+j.X()*im, d.Y()+j.Y()*im)
+	}
+}
+
+// A Joint holds two anchors together. Joints are added to worlds, which
+// solve them alongside contacts each step.
+type Joint interface {
+	// Anchors returns the two anchors the joint holds.
+	Anchors() (Anchor, Anchor)
+	// prepare readies the joint for a step of length h.
+	prepare(h float64)
+	// solve applies impulses to the joint's anchors to satisfy it.
+	solve(h float64)
+	// breakForce returns how much force may be applied to the joint before
+	// it breaks, or 0 if it never breaks, and the force applied to the
+	// joint in the last step.
+	breakForce(h float64) (limit, force float64)
+}
+
+// joint holds what is common to all joints.
+type joint struct {
+	A, B Anchor
+	// BreakForce is the greatest force the joint can apply to its anchors
+	// without breaking. If it is 0, the joint never breaks.
+	BreakForce float64
+	// impulse is the total impulse applied to the joint this step
+	impulse float64
+}
+
+// Anchors satisfies Joint.
+func (j *joint) Anchors() (Anchor, Anchor) {
+	return j.A, j.B
+}
+
+func (j *joint) breakForce(h float64) (float64, float64) {
+	return j.BreakForce, math.Abs(j.impulse) / h
+}
+
+// axis returns the unit vector from A to B, and the distance between them.
+func (j *joint) axis() (floatgeom.Point2, float64) {
+	d := j.B.pos().Sub(j.A.pos())
+	l := d.Magnitude()
+	if l == 0 {
+		return floatgeom.Point2{}, 0
+	}
+	return d.DivConst(l), l
+}
+
+// solveAxis applies an impulse along n, pushing B along n and A against
+// it, such that their relative speed along n becomes target. The total
+// impulse applied along n this step, which is kept in total, is kept
+// between min and max.
+func (j *joint) solveAxis(total *float64, n floatgeom.Point2, target, min, max float64) {
+	k := j.A.invMass() + j.B.invMass()
+	if k == 0 || n == (floatgeom.Point2{}) {
+		return
+	}
+	v := j.B.vel().Sub(j.A.vel()).Dot(n)
+	next := math.Max(min, math.Min(max, *total+(target-v)/k))
+	p := n.MulConst(next - *total)
+	*total = next
+	j.A.applyImpulse(p.MulConst(-1))
+	j.B.applyImpulse(p)
+}
+
+// A DistanceJoint keeps its anchors Length apart, like a rod, or at most
+// Length apart, like a rope.
+type DistanceJoint struct {
+	joint
+	Length float64
+	// Rope makes the joint only pull its anchors together.
+	Rope bool
+	// err is how far the anchors are from Length at the start of the step
+	err float64
+}
+
+// NewDistanceJoint returns a joint which keeps its anchors as far apart
+// as they are now.
+func NewDistanceJoint(a, b Anchor) *DistanceJoint {
+	j := &DistanceJoint{joint: joint{A: a, B: b}}
+	_, j.Length = j.axis()
+	return j
+}
+
+func (j *DistanceJoint) prepare(h float64) {
+	_, l := j.axis()
+	j.err = l - j.Length
+	j.impulse = 0
+}
+
+func (j *DistanceJoint) solve(h float64) {
+	if j.Rope && j.err <= 0 {
+		return
+	}
+	n, _ := j.axis()
+	max := math.Inf(1)
+	if j.Rope {
+		max = 0
+	}
+	j.solveAxis(&j.impulse, n, -baumgarte*j.err/h, math.Inf(-1), max)
+}
+
+// A SpringJoint pulls and pushes its anchors toward being RestLength apart
+// with a force proportional to how far they are from it. Unlike other
+// joints, springs are not solved exactly, and can stretch.
+type SpringJoint struct {
+	joint
+	RestLength float64
+	// Stiffness is the force the spring applies per unit it is stretched or
+	// compressed.
+	Stiffness float64
+	// Damping is the force the spring applies against its anchors moving
+	// apart or together, per unit of their relative speed.
+	Damping float64
+}
+
+// NewSpringJoint returns a spring which rests with its anchors as far
+// apart as they are now.
+func NewSpringJoint(a, b Anchor, stiffness, damping float64) *SpringJoint {
+	j := &SpringJoint{
+		joint:     joint{A: a, B: b},
+		Stiffness: stiffness,
+		Damping:   damping,
+	}
+	_, j.RestLength = j.axis()
+	return j
+}
+
+func (j *SpringJoint) prepare(h float64) {
+	n, l := j.axis()
+	v := j.B.vel().Sub(j.A.vel()).Dot(n)
+	f := -j.Stiffness*(l-j.RestLength) - j.Damping*v
+	j.impulse = f * h
+	p := n.MulConst(j.impulse)
+	j.A.applyImpulse(p.MulConst(-1))
+	j.B.applyImpulse(p)
+}
+
+func (j *SpringJoint) solve(float64) {}
+
+// A RevoluteJoint is a hinge between a pivot, A, and an arm, B. The arm is
+// kept Length from the pivot, and can swing around it. As bodies do not
+// rotate, the arm's body keeps its orientation as it swings.
+type RevoluteJoint struct {
+	joint
+	Length float64
+	// If Limited, the angle of the arm around the pivot, in degrees, is
+	// kept between MinAngle and MaxAngle. Angles are measured as
+	// physics.Vector.Angle measures them.
+	Limited            bool
+	MinAngle, MaxAngle float64
+	// angleImpulse is the total impulse applied to keep the arm's angle
+	// within its limits this step
+	angleImpulse  float64
+	err, angleErr float64
+}
+
+// NewRevoluteJoint returns a hinge whose arm is as far from its pivot as
+// it is now.
+func NewRevoluteJoint(pivot, arm Anchor) *RevoluteJoint {
+	j := &RevoluteJoint{joint: joint{A: pivot, B: arm}}
+	_, j.Length = j.axis()
+	return j
+}
+
+func (j *RevoluteJoint) prepare(h float64) {
+	n, l := j.axis()
+	j.err = l - j.Length
+	j.impulse, j.angleImpulse = 0, 0
+	j.angleErr = 0
+	if !j.Limited {
+		return
+	}
+	// Angles are compared to the middle of the limits, so limits which
+	// cross from -180 to 180 work
+	mid := (j.MinAngle + j.MaxAngle) / 2
+	half := (j.MaxAngle - j.MinAngle) / 2
+	d := math.Remainder(math.Atan2(n.Y(), n.X())*alg.RadToDeg-mid, 360)
+	// Errors are measured along the arc the arm swings through
+	switch {
+	case d < -half:
+		j.angleErr = l * (d + half) * alg.DegToRad
+	case d > half:
+		j.angleErr = l * (d - half) * alg.DegToRad
+	}
+}
+
+func (j *RevoluteJoint) solve(h float64) {
+	n, _ := j.axis()
+	j.solveAxis(&j.impulse, n, -baumgarte*j.err/h, math.Inf(-1), math.Inf(1))
+	if j.angleErr == 0 {
+		return
+	}
+	// Along the tangent, the arm's angle increases
+	t := tangent(n)
+	if j.angleErr < 0 {
+		j.solveAxis(&j.angleImpulse, t, -baumgarte*j.angleErr/h, 0, math.Inf(1))
+	} else {
+		j.solveAxis(&j.angleImpulse, t, -baumgarte*j.angleErr/h, math.Inf(-1), 0)
+	}
+}
+
+func (j *RevoluteJoint) breakForce(h float64) (float64, float64) {
+	return j.BreakForce, math.Hypot(j.impulse, j.angleImpulse) / h
+}
+
+// A PulleyJoint hangs its anchors from ropes over two fixed points, GroundA
+// and GroundB, which are joined by a single rope. The length of A's rope
+// plus Ratio times the length of B's rope is at most Total, so pulling one
+// anchor down lifts the other.
+type PulleyJoint struct {
+	joint
+	GroundA, GroundB floatgeom.Point2
+	Ratio            float64
+	Total            float64
+	err              float64
+}
+
+// NewPulleyJoint returns a pulley whose rope is as long as it is now. Ratio
+// must be positive.
+func NewPulleyJoint(a, b Anchor, groundA, groundB floatgeom.Point2, ratio float64) (*PulleyJoint, error) {
+	if ratio <= 0 {
+		return nil, oakerr.InvalidInput{InputName: "ratio"}
+	}
+	j := &PulleyJoint{
+		joint:   joint{A: a, B: b},
+		GroundA: groundA,
+		GroundB: groundB,
+		Ratio:   ratio,
+	}
+	_, la, _, lb := j.ropes()
+	j.Total = la + ratio*lb
+	return j, nil
+}
+
+// ropes returns the directions from each ground point to its anchor, and
+// the lengths of each rope.
+func (j *PulleyJoint) ropes() (floatgeom.Point2, float64, floatgeom.Point2, float64) {
+	da := j.A.pos().Sub(j.GroundA)
+	db := j.B.pos().Sub(j.GroundB)
+	la, lb := da.Magnitude(), db.Magnitude()
+	if la != 0 {
+		da = da.DivConst(la)
+	}
+	if lb != 0 {
+		db = db.DivConst(lb)
+	}
+	return da, la, db, lb
+}
+
+func (j *PulleyJoint) prepare(h float64) {
+	_, la, _, lb := j.ropes()
+	j.err = la + j.Ratio*lb - j.Total
+	j.impulse = 0
+}
+
+func (j *PulleyJoint) solve(h float64) {
+	if j.err <= 0 {
+		return
+	}
+	ua, _, ub, _ := j.ropes()
+	k := j.A.invMass() + j.Ratio*j.Ratio*j.B.invMass()
+	if k == 0 {
+		return
+	}
+	v := j.A.vel().Dot(ua) + j.Ratio*j.B.vel().Dot(ub)
+	// The rope can only pull
+	total := math.Min(0, j.impulse+(-baumgarte*j.err/h-v)/k)
+	d := total - j.impulse
+	j.impulse = total
+	j.A.applyImpulse(ua.MulConst(d))
+	j.B.applyImpulse(ub.MulConst(d * j.Ratio))
+}
@@ -0,0 +1,174 @@
+package rigid
+
+import (
+	"math"
+	"testing"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+	"github.com/stretchr/testify/assert"
+)
+
+func newJointWorld(t *testing.T) *World {
+	tree, err := collision.NewTree()
+	assert.Nil(t, err)
+	w := NewWorld(tree)
+	w.Gravity.SetPos(0, .4)
+	return w
+}
+
+func center(b *Body) floatgeom.Point2 {
+	x, y := b.Space.GetCenter()
+	return floatgeom.Point2{x, y}
+}
+
+func TestDistanceJoint(t *testing.T) {
+	w := newJointWorld(t)
+	defer w.Stop()
+	b := newTestBody(t, w, 45, 25)
+	pivot := floatgeom.Point2{50, 0}
+	j := NewDistanceJoint(Anchor{Offset: pivot}, Anchor{Body: b, Offset: floatgeom.Point2{5, 5}})
+	assert.Equal(t, 30.0, j.Length)
+	assert.Nil(t, w.AddJoints(j))
+	// Push the body into swinging
+	b.Velocity.SetX(3)
+	for i := 0; i < 100; i++ {
+		w.Step()
+		assert.InDelta(t, 30, center(b).Distance(pivot), .5)
+	}
+
+	// Ropes let their anchors come closer
+	rope := newTestBody(t, w, 100, 0)
+	j2 := NewDistanceJoint(Anchor{Offset: floatgeom.Point2{105, -10}}, Anchor{Body: rope, Offset: floatgeom.Point2{5, 5}})
+	j2.Length = 40
+	j2.Rope = true
+	assert.Nil(t, w.AddJoints(j2))
+	for i := 0; i < 5; i++ {
+		w.Step()
+	}
+	assert.True(t, rope.Y() > 0)
+	for i := 0; i < 100; i++ {
+		w.Step()
+	}
+	assert.InDelta(t, 40, center(rope).Distance(floatgeom.Point2{105, -10}), .5)
+}
+
+func TestSpringJoint(t *testing.T) {
+	w := newJointWorld(t)
+	defer w.Stop()
+	w.Gravity.Zero()
+	a := newTestBody(t, w, 0, 0)
+	b := newTestBody(t, w, 50, 0)
+	j := NewSpringJoint(Anchor{Body: a}, Anchor{Body: b}, .1, .2)
+	assert.Equal(t, 50.0, j.RestLength)
+	j.RestLength = 30
+	assert.Nil(t, w.AddJoints(j))
+	for i := 0; i < 200; i++ {
+		w.Step()
+	}
+	assert.InDelta(t, 30, b.X()-a.X(), .5)
+	// Equal masses meet in the middle
+	assert.InDelta(t, 10, a.X(), .5)
+}
+
+func TestRevoluteJoint(t *testing.T) {
+	w := newJointWorld(t)
+	defer w.Stop()
+	b := newTestBody(t, w, 25, -5)
+	j := NewRevoluteJoint(Anchor{}, Anchor{Body: b, Offset: floatgeom.Point2{5, 5}})
+	j.Limited = true
+	j.MinAngle = -10
+	j.MaxAngle = 45
+	assert.Nil(t, w.AddJoints(j))
+	for i := 0; i < 200; i++ {
+		w.Step()
+	}
+	c := center(b)
+	assert.InDelta(t, 30, c.Magnitude(), .5)
+	assert.InDelta(t, 45, math.Atan2(c.Y(), c.X())*180/math.Pi, 1)
+}
+
+func TestPulleyJoint(t *testing.T) {
+	w := newJointWorld(t)
+	defer w.Stop()
+	light := newTestBody(t, w, 0, 50)
+	heavy := newTestBody(t, w, 100, 50)
+	heavy.SetMass(2)
+	ga, gb := floatgeom.Point2{5, 0}, floatgeom.Point2{105, 0}
+	_, err := NewPulleyJoint(Anchor{Body: light}, Anchor{Body: heavy}, ga, gb, 0)
+	assert.Equal(t, oakerr.InvalidInput{InputName: "ratio"}, err)
+	j, err := NewPulleyJoint(Anchor{Body: light, Offset: floatgeom.Point2{5, 0}},
+		Anchor{Body: heavy, Offset: floatgeom.Point2{5, 0}}, ga, gb, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 100.0, j.Total)
+	assert.Nil(t, w.AddJoints(j))
+	for i := 0; i < 20; i++ {
+		w.Step()
+	}
+	assert.True(t, light.Y() < 40)
+	assert.True(t, heavy.Y() > 60)
+	assert.InDelta(t, 100, heavy.Y()+light.Y(), .5)
+}
+
+type pushed struct {
+	physics.Vector
+	physics.Mass
+	delta physics.Vector
+}
+
+func (p *pushed) GetDelta() physics.Vector {
+	return p.delta
+}
+
+func TestJointedNonBodies(t *testing.T) {
+	w := newJointWorld(t)
+	defer w.Stop()
+	b := newTestBody(t, w, 0, 0)
+	p := &pushed{Vector: physics.NewVector(0, -20), delta: physics.NewVector(0, 0)}
+	p.SetMass(1)
+	assert.Nil(t, w.AddJoints(NewDistanceJoint(Anchor{Body: b}, Anchor{Body: p})))
+	w.Step()
+	// The body is held up by what it hangs from, which is pulled down
+	assert.True(t, p.delta.Y() > 0)
+	assert.True(t, b.Velocity.Y() < .4)
+
+	// Removing a body removes its joints
+	assert.Equal(t, 1, w.Remove(b))
+	assert.Empty(t, w.Joints())
+}
+
+func TestJointBreak(t *testing.T) {
+	event.DefaultBus.SetSynchronous(true)
+	defer event.DefaultBus.SetSynchronous(false)
+
+	w := newJointWorld(t)
+	defer w.Stop()
+	b := newTestBody(t, w, 0, 10)
+	j := NewDistanceJoint(Anchor{}, Anchor{Body: b})
+	j.BreakForce = .5
+	j2 := NewDistanceJoint(Anchor{}, Anchor{Body: b})
+	assert.NotNil(t, w.AddJoints(j, nil))
+	assert.Nil(t, w.AddJoints(j, j2))
+	assert.Equal(t, 1, w.RemoveJoints(j2))
+	assert.Equal(t, []Joint{j}, w.Joints())
+
+	var broken []BreakEvent
+	event.GlobalBind(func(_ int, data interface{}) int {
+		broken = append(broken, data.(BreakEvent))
+		return 0
+	}, JointBreak)
+	assert.Nil(t, event.Flush())
+	w.Step()
+	assert.Empty(t, broken)
+	assert.Equal(t, []Joint{j}, w.Joints())
+
+	w.Gravity.SetPos(0, 1)
+	w.Step()
+	assert.Len(t, broken, 1)
+	assert.Equal(t, j, broken[0].Joint)
+	assert.True(t, broken[0].Force > .5)
+	assert.Empty(t, w.Joints())
+}
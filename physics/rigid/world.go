@@ -0,0 +1,559 @@
+package rigid
+
+import (
+	"math"
+	"sync"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+)
+
+const (
+	// slop is how deep contacts may be before bodies are pushed apart,
+	// so bodies resting on each other do not jitter
+	slop = .01
+	// correction is the fraction of a contact's depth that bodies are
+	// pushed apart by each step
+	correction = .8
+	// bounceSpeed is the slowest speed at which bodies bounce
+	bounceSpeed = .5
+	// wakeMargin is how close a sleeping body must be to a space which
+	// moved or was removed to be woken
+	wakeMargin = 1
+)
+
+// A World moves bodies through a collision tree. Every frame it is
+// stepped, a world adds gravity and forces to the velocity of its dynamic
+// bodies, moves its bodies by their velocities, and pushes apart bodies
+// which overlap each other or other spaces in the tree with impulses
+// which make them bounce and slide. Each frame is split into a fixed
+// number of sub-steps, which resolves collisions between fast bodies more
+// accurately. Bodies are not swept, so a body which moves further than a
+// space is thick in a single sub-step can still pass through it.
+//
+// Velocities are measured in units per frame, and gravity and forces in
+// units per frame per frame, as entities.Moving's Delta is.
+type World struct {
+	// Gravity is added to the velocity of each dynamic body every frame,
+	// scaled by the body's GravityScale.
+	Gravity physics.Vector
+	// SubSteps is how many steps each frame is split into.
+	SubSteps int
+	// Iterations is how many times the contacts of each sub-step are
+	// solved. More iterations make stacks of bodies steadier.
+	Iterations int
+	// A body which moves slower than SleepSpeed for SleepFrames frames
+	// falls asleep. If SleepFrames is 0, bodies never sleep.
+	SleepSpeed  float64
+	SleepFrames int
+
+	tree  *collision.Tree
+	solid []collision.Label
+
+	sync.Mutex
+	bodies []*Body
+	index  map[*collision.Space]int
+	// impulses are the impulses applied to each contact in the last step
+	impulses map[contactKey][2]float64
+	joints   []Joint
+	// broken are the joints broken in the current step
+	broken []BreakEvent
+
+	// changes are the changes made to tree since the last step, which are
+	// recorded separately as the world moves spaces while it is locked
+	changesLock sync.Mutex
+	changes     []collision.Change
+	unwatch     func()
+	stop        func()
+}
+
+// NewWorld returns a world which moves bodies through the given tree, or
+// through DefTree if tree is nil. If any labels are given, bodies only
+// collide with spaces with one of those labels. Spaces are also only
+// collided with if their labels can collide by the tree's Matrix. The
+// world has no gravity, 4 sub-steps, 8 iterations, and bodies which move
+// slower than .05 for 30 frames fall asleep.
+func NewWorld(tree *collision.Tree, solid ...collision.Label) *World {
+	if tree == nil {
+		tree = collision.DefTree
+	}
+	w := &World{
+		Gravity:     physics.NewVector(0, 0),
+		SubSteps:    4,
+		Iterations:  8,
+		SleepSpeed:  .05,
+		SleepFrames: 30,
+		tree:        tree,
+		solid:       solid,
+		index:       make(map[*collision.Space]int),
+	}
+	w.unwatch = tree.Watch(func(c collision.Change) {
+		w.changesLock.Lock()
+		w.changes = append(w.changes, c)
+		w.changesLock.Unlock()
+	})
+	return w
+}
+
+// Start steps the world at the start of every frame until it is stopped.
+func (w *World) Start() {
+	stop := event.EveryFrame(w.Step)
+	w.Lock()
+	w.stop = stop
+	w.Unlock()
+}
+
+// Stop stops the world from being stepped every frame and from watching
+// its tree. A stopped world should not be stepped again.
+func (w *World) Stop() {
+	w.Lock()
+	stop := w.stop
+	w.Unlock()
+	if stop != nil {
+		stop()
+	}
+	w.unwatch()
+}
+
+// Add adds bodies to the world. The spaces of bodies should already be
+// in the world's tree. Bodies which are removed from the tree are removed
+// from the world.
+func (w *World) Add(bs ...*Body) error {
+	for _, b := range bs {
+		if b == nil {
+			return oakerr.NilInput{InputName: "bs"}
+		}
+	}
+	w.Lock()
+	defer w.Unlock()
+	for _, b := range bs {
+		if _, ok := w.index[b.Space]; ok {
+			return oakerr.ExistingElement{InputName: "bs", InputType: "*Body"}
+		}
+		w.index[b.Space] = len(w.bodies)
+		w.bodies = append(w.bodies, b)
+	}
+	return nil
+}
+
+// Remove removes bodies from the world, returning how many were removed.
+// Their spaces are left in the world's tree, and their joints are removed
+// from the world.
+func (w *World) Remove(bs ...*Body) int {
+	w.Lock()
+	defer w.Unlock()
+	removed := 0
+	for _, b := range bs {
+		if b != nil && w.remove(b.Space) {
+			removed++
+		}
+	}
+	return removed
+}
+
+func (w *World) remove(sp *collision.Space) bool {
+	i, ok := w.index[sp]
+	if !ok {
+		return false
+	}
+	b := w.bodies[i]
+	joints := w.joints[:0]
+	for _, j := range w.joints {
+		if a1, a2 := j.Anchors(); a1.Body != Jointed(b) && a2.Body != Jointed(b) {
+			joints = append(joints, j)
+		}
+	}
+	w.joints = joints
+	last := len(w.bodies) - 1
+	w.bodies[i] = w.bodies[last]
+	w.index[w.bodies[i].Space] = i
+	w.bodies[last] = nil
+	w.bodies = w.bodies[:last]
+	delete(w.index, sp)
+	return true
+}
+
+// Bodies returns the bodies in the world.
+func (w *World) Bodies() []*Body {
+	w.Lock()
+	defer w.Unlock()
+	return append([]*Body{}, w.bodies...)
+}
+
+// AddJoints adds joints to the world. Joints may hold bodies in the world,
+// anything else Jointed, and fixed points.
+func (w *World) AddJoints(js ...Joint) error {
+	for _, j := range js {
+		if j == nil {
+			return oakerr.NilInput{InputName: "js"}
+		}
+	}
+	w.Lock()
+	w.joints = append(w.joints, js...)
+	w.Unlock()
+	return nil
+}
+
+// RemoveJoints removes joints from the world, returning how many were
+// removed.
+func (w *World) RemoveJoints(js ...Joint) int {
+	w.Lock()
+	defer w.Unlock()
+	removed := 0
+	for _, j := range js {
+		for i, j2 := range w.joints {
+			if j == j2 {
+				w.joints = append(w.joints[:i], w.joints[i+1:]...)
+				removed++
+				break
+			}
+		}
+	}
+	return removed
+}
+
+// Joints returns the joints in the world.
+func (w *World) Joints() []Joint {
+	w.Lock()
+	defer w.Unlock()
+	return append([]Joint{}, w.joints...)
+}
+
+// Step advances the world by one frame. JointBreak is triggered for each
+// joint which broke once the step is done.
+func (w *World) Step() {
+	w.Lock()
+	w.step()
+	broken := w.broken
+	w.broken = nil
+	w.Unlock()
+	for _, ev := range broken {
+		event.Trigger(JointBreak, ev)
+	}
+}
+
+func (w *World) step() {
+	w.applyChanges()
+	steps := w.SubSteps
+	if steps < 1 {
+		steps = 1
+	}
+	h := 1 / float64(steps)
+	for i := 0; i < steps; i++ {
+		w.subStep(h, w.SleepFrames*steps)
+	}
+	for _, b := range w.bodies {
+		b.force.Zero()
+		b.Vector.SetPos(b.Space.X(), b.Space.Y())
+	}
+}
+
+// applyChanges removes bodies whose spaces were removed from the tree, and
+// wakes sleeping bodies near spaces which moved or were removed, since
+// they may have been resting on or pushed by them.
+func (w *World) applyChanges() {
+	w.changesLock.Lock()
+	changes := w.changes
+	w.changes = nil
+	w.changesLock.Unlock()
+	for _, c := range changes {
+		switch c.Kind {
+		case collision.Cleared:
+			w.bodies = nil
+			w.index = make(map[*collision.Space]int)
+		case collision.Removed:
+			w.remove(c.Space)
+			w.wakeNear(c.From)
+		case collision.Moved:
+			// Dynamic bodies wake the bodies they move into themselves,
+			// and waking every body they move past would keep resting
+			// bodies waking each other forever
+			if i, ok := w.index[c.Space]; ok && w.bodies[i].Type == Dynamic {
+				continue
+			}
+			w.wakeNear(c.From.GreaterOf(c.To))
+		}
+	}
+}
+
+func (w *World) wakeNear(r floatgeom.Rect3) {
+	for i := 0; i < 2; i++ {
+		r.Min[i] -= wakeMargin
+		r.Max[i] += wakeMargin
+	}
+	for _, sp := range w.tree.SearchIntersect(r) {
+		if i, ok := w.index[sp]; ok && w.bodies[i].sleeping {
+			w.bodies[i].Wake()
+		}
+	}
+}
+
+// A contactKey identifies a contact between steps.
+type contactKey struct {
+	a     *Body
+	other *collision.Space
+}
+
+// A contact is a body overlapping another body or space.
+type contact struct {
+	contactKey
+	// b is nil if a overlaps a space which is not a body
+	b *Body
+	// normal points from b to a
+	normal floatgeom.Point2
+	depth  float64
+	// invMass is the sum of the inverse masses of a and b
+	invMass float64
+	// bounce is the speed along the normal which a and b should separate at
+	bounce   float64
+	friction float64
+	// normalImpulse and frictionImpulse are the impulses applied to the
+	// contact so far this step
+	normalImpulse, frictionImpulse float64
+}
+
+func (w *World) subStep(h float64, sleepSteps int) {
+	contacts, woken := w.contacts()
+	for _, b := range w.bodies {
+		if im := b.invMass(); im != 0 {
+			g := b.GravityScale * h
+			b.Velocity.SetPos(
+				b.Velocity.X()+w.Gravity.X()*g+b.force.X()*im*h,
+				b.Velocity.Y()+w.Gravity.Y()*g+b.force.Y()*im*h,
+			)
+		}
+	}
+	// Contacts start with the impulses they ended the last step with, so
+	// stacks of resting bodies do not need to be solved from scratch
+	for j := range contacts {
+		c := &contacts[j]
+		if imp, ok := w.impulses[c.contactKey]; ok {
+			c.normalImpulse, c.frictionImpulse = imp[0], imp[1]
+			applyImpulse(c, c.normal.MulConst(imp[0]).Add(tangent(c.normal).MulConst(imp[1])))
+		}
+	}
+	for _, j := range w.joints {
+		j.prepare(h)
+	}
+	for i := 0; i < w.Iterations; i++ {
+		for j := range contacts {
+			solve(&contacts[j])
+		}
+		for _, j := range w.joints {
+			j.solve(h)
+		}
+	}
+	w.breakJoints(h)
+	w.impulses = make(map[contactKey][2]float64, len(contacts))
+	for _, c := range contacts {
+		w.impulses[c.contactKey] = [2]float64{c.normalImpulse, c.frictionImpulse}
+	}
+	w.move(contacts, h)
+
+	woken = append(woken, w.jointWoken()...)
+	for _, b := range woken {
+		b.Wake()
+	}
+	if sleepSteps > 0 {
+		for _, b := range w.bodies {
+			if b.Type != Dynamic || b.sleeping {
+				continue
+			}
+			if b.Velocity.Magnitude() >= w.SleepSpeed || b.force.X() != 0 || b.force.Y() != 0 {
+				b.still = 0
+				continue
+			}
+			b.still++
+			if b.still >= sleepSteps {
+				b.sleeping = true
+				b.Velocity.Zero()
+			}
+		}
+	}
+}
+
+// breakJoints removes the joints which were pulled harder than their
+// BreakForce this step.
+func (w *World) breakJoints(h float64) {
+	joints := w.joints[:0]
+	for _, j := range w.joints {
+		limit, force := j.breakForce(h)
+		if limit > 0 && force > limit {
+			w.broken = append(w.broken, BreakEvent{Joint: j, Force: force})
+			continue
+		}
+		joints = append(joints, j)
+	}
+	w.joints = joints
+}
+
+// jointWoken returns the sleeping bodies held by joints to anything moving
+// faster than SleepSpeed.
+func (w *World) jointWoken() []*Body {
+	var woken []*Body
+	for _, j := range w.joints {
+		a, b := j.Anchors()
+		if sleeper, ok := a.Body.(*Body); ok && sleeper.sleeping && b.vel().Magnitude() >= w.SleepSpeed {
+			woken = append(woken, sleeper)
+		}
+		if sleeper, ok := b.Body.(*Body); ok && sleeper.sleeping && a.vel().Magnitude() >= w.SleepSpeed {
+			woken = append(woken, sleeper)
+		}
+	}
+	return woken
+}
+
+// contacts finds every contact of an awake dynamic body, and the sleeping
+// bodies which awake bodies are moving into.
+func (w *World) contacts() (contacts []contact, woken []*Body) {
+	for i, a := range w.bodies {
+		if a.Type != Dynamic || a.sleeping {
+			continue
+		}
+		for _, c := range w.tree.Contacts(a.Space) {
+			if !w.isSolid(c.Space) {
+				continue
+			}
+			var b *Body
+			if j, ok := w.index[c.Space]; ok {
+				b = w.bodies[j]
+				// Contacts between awake dynamic bodies are found from
+				// both bodies, and only kept from the first
+				if b.Type == Dynamic && !b.sleeping && j < i {
+					continue
+				}
+			}
+			depth := c.MTV.Magnitude()
+			if depth == 0 {
+				continue
+			}
+			ct := contact{
+				contactKey: contactKey{
+					a:     a,
+					other: c.Space,
+				},
+				b:        b,
+				normal:   floatgeom.Point2{c.MTV.X() / depth, c.MTV.Y() / depth},
+				depth:    depth,
+				invMass:  a.invMass(),
+				friction: a.Friction,
+			}
+			restitution := a.Restitution
+			if b != nil {
+				ct.invMass += b.invMass()
+				ct.friction = math.Sqrt(a.Friction * b.Friction)
+				restitution = math.Max(a.Restitution, b.Restitution)
+			}
+			if ct.invMass == 0 {
+				continue
+			}
+			vn := relativeVelocity(&ct).Dot(ct.normal)
+			if vn < -bounceSpeed {
+				ct.bounce = -restitution * vn
+			}
+			if b != nil && b.sleeping && vn < -w.SleepSpeed {
+				woken = append(woken, b)
+			}
+			contacts = append(contacts, ct)
+		}
+	}
+	return contacts, woken
+}
+
+func (w *World) isSolid(sp *collision.Space) bool {
+	return len(w.solid) == 0 || sp.HasLabel(w.solid...)
+}
+
+// relativeVelocity returns the velocity of a relative to b.
+func relativeVelocity(c *contact) floatgeom.Point2 {
+	v := floatgeom.Point2{c.a.Velocity.X(), c.a.Velocity.Y()}
+	if c.b != nil && !c.b.sleeping {
+		v = v.Sub(floatgeom.Point2{c.b.Velocity.X(), c.b.Velocity.Y()})
+	}
+	return v
+}
+
+// solve applies impulses to the bodies of a contact to stop them moving
+// into each other and to slow them sliding along each other. The total
+// impulse applied to each contact is clamped rather than each individual
+// impulse, per "Iterative Dynamics with Temporal Coherence" by E. Catto,
+// Game Developers Conference, 2005.
+func solve(c *contact) {
+	vn := relativeVelocity(c).Dot(c.normal)
+	total := math.Max(c.normalImpulse+(c.bounce-vn)/c.invMass, 0)
+	applyImpulse(c, c.normal.MulConst(total-c.normalImpulse))
+	c.normalImpulse = total
+
+	tangent := tangent(c.normal)
+	vt := relativeVelocity(c).Dot(tangent)
+	limit := c.friction * c.normalImpulse
+	total = math.Max(-limit, math.Min(limit, c.frictionImpulse-vt/c.invMass))
+	applyImpulse(c, tangent.MulConst(total-c.frictionImpulse))
+	c.frictionImpulse = total
+}
+
+func tangent(normal floatgeom.Point2) floatgeom.Point2 {
+	return floatgeom.Point2{-normal.Y(), normal.X()}
+}
+
+// applyImpulse applies j to a, and the opposite of j to b.
+func applyImpulse(c *contact, j floatgeom.Point2) {
+	if im := c.a.invMass(); im != 0 {
+		c.a.Velocity.SetPos(c.a.Velocity.X()+j.X()*im, c.a.Velocity.Y()+j.Y()*im)
+	}
+	if c.b == nil {
+		return
+	}
+	if im := c.b.invMass(); im != 0 {
+		c.b.Velocity.SetPos(c.b.Velocity.X()-j.X()*im, c.b.Velocity.Y()-j.Y()*im)
+	}
+}
+
+// move moves each body by its velocity, and pushes the bodies of each
+// contact out of each other, as impulses only stop bodies moving further
+// into each other. Bodies are left overlapping by slop, so the contacts of
+// resting bodies are found again the next step.
+func (w *World) move(contacts []contact, h float64) {
+	offsets := make(map[*Body]floatgeom.Point2)
+	for _, b := range w.bodies {
+		if b.Type == Static || b.sleeping || (b.Velocity.X() == 0 && b.Velocity.Y() == 0) {
+			continue
+		}
+		offsets[b] = floatgeom.Point2{b.Velocity.X() * h, b.Velocity.Y() * h}
+	}
+	for _, c := range contacts {
+		d := math.Max(c.depth-slop, 0) * correction / c.invMass
+		if d == 0 {
+			continue
+		}
+		push := c.normal.MulConst(d)
+		if im := c.a.invMass(); im != 0 {
+			offsets[c.a] = offsets[c.a].Add(push.MulConst(im))
+		}
+		if c.b == nil {
+			continue
+		}
+		if im := c.b.invMass(); im != 0 {
+			offsets[c.b] = offsets[c.b].Sub(push.MulConst(im))
+		}
+	}
+	moves := make([]collision.SpaceUpdate, 0, len(offsets))
+	for b, off := range offsets {
+		moves = append(moves, collision.SpaceUpdate{
+			Space:    b.Space,
+			Location: shift(b.Space.Location, off.X(), off.Y()),
+		})
+	}
+	w.tree.UpdateSpaces(moves...)
+}
+
+func shift(r floatgeom.Rect3, x, y float64) floatgeom.Rect3 {
+	r.Min[0] += x
+	r.Max[0] += x
+	r.Min[1] += y
+	r.Max[1] += y
+	return r
+}
@@ -0,0 +1,189 @@
+package rigid
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+	"github.com/stretchr/testify/assert"
+)
+
+const ground collision.Label = 1
+
+func newTestWorld(t *testing.T) (*World, *collision.Tree, *collision.Space) {
+	tree, err := collision.NewTree()
+	assert.Nil(t, err)
+	floor := collision.NewLabeledSpace(0, 100, 200, 20, ground)
+	tree.Add(floor)
+	w := NewWorld(tree)
+	return w, tree, floor
+}
+
+func newTestBody(t *testing.T, w *World, x, y float64) *Body {
+	sp := collision.NewUnassignedSpace(x, y, 10, 10)
+	w.tree.Add(sp)
+	b, err := NewBody(sp, 1)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Add(b))
+	return b
+}
+
+func TestBodyErrors(t *testing.T) {
+	_, err := NewBody(nil, 1)
+	assert.Equal(t, oakerr.NilInput{InputName: "sp"}, err)
+	_, err = NewBody(collision.NewUnassignedSpace(0, 0, 1, 1), 0)
+	assert.Equal(t, oakerr.InvalidInput{InputName: "inMass"}, err)
+
+	w, _, _ := newTestWorld(t)
+	defer w.Stop()
+	b := newTestBody(t, w, 0, 0)
+	assert.NotNil(t, w.Add(nil))
+	assert.NotNil(t, w.Add(b))
+	assert.Equal(t, []*Body{b}, w.Bodies())
+	assert.Equal(t, 1, w.Remove(b, nil))
+	assert.Equal(t, 0, w.Remove(b))
+	assert.Empty(t, w.Bodies())
+}
+
+func TestWorldFallAndSleep(t *testing.T) {
+	w, tree, floor := newTestWorld(t)
+	defer w.Stop()
+	w.Gravity.SetPos(0, .4)
+	b := newTestBody(t, w, 50, 0)
+	for i := 0; i < 200; i++ {
+		w.Step()
+	}
+	assert.InDelta(t, 90, b.Y(), .1)
+	assert.Equal(t, 50.0, b.X())
+	assert.True(t, b.Sleeping())
+	assert.Equal(t, 0.0, b.Velocity.Magnitude())
+
+	// Removing the floor wakes bodies resting on it
+	tree.Remove(floor)
+	w.Step()
+	assert.False(t, b.Sleeping())
+	assert.True(t, b.Y() > 90)
+
+	// As does applying a force
+	b.sleeping = true
+	b.ApplyForce(physics.NewVector(1, 0))
+	assert.False(t, b.Sleeping())
+}
+
+func TestWorldStack(t *testing.T) {
+	w, _, _ := newTestWorld(t)
+	defer w.Stop()
+	w.Gravity.SetPos(0, .4)
+	var bs []*Body
+	for i := 0; i < 5; i++ {
+		bs = append(bs, newTestBody(t, w, 50+float64(i), 80-float64(i)*12))
+	}
+	for i := 0; i < 300; i++ {
+		w.Step()
+	}
+	for i, b := range bs {
+		assert.InDelta(t, 90-float64(i)*10, b.Y(), .1)
+		assert.True(t, b.Sleeping())
+	}
+}
+
+func TestWorldBounce(t *testing.T) {
+	w, _, _ := newTestWorld(t)
+	defer w.Stop()
+	b := newTestBody(t, w, 50, 80)
+	b.Restitution = 1
+	b.Velocity.SetY(5)
+	for i := 0; i < 4; i++ {
+		w.Step()
+	}
+	assert.InDelta(t, -5, b.Velocity.Y(), .01)
+	assert.True(t, b.Y() < 90)
+
+	// Slow bodies do not bounce
+	b2 := newTestBody(t, w, 150, 89.8)
+	b2.Restitution = 1
+	b2.Velocity.SetY(.4)
+	w.Step()
+	assert.InDelta(t, 0, b2.Velocity.Y(), .01)
+}
+
+func TestWorldFriction(t *testing.T) {
+	w, _, _ := newTestWorld(t)
+	defer w.Stop()
+	w.Gravity.SetPos(0, .4)
+	rough := newTestBody(t, w, 10, 90)
+	rough.Friction = 1
+	rough.Velocity.SetX(2)
+	smooth := newTestBody(t, w, 100, 90)
+	smooth.Friction = 0
+	smooth.Velocity.SetX(2)
+	for i := 0; i < 5; i++ {
+		w.Step()
+	}
+	assert.InDelta(t, 0, rough.Velocity.X(), .01)
+	assert.InDelta(t, 2, smooth.Velocity.X(), .01)
+}
+
+func TestWorldBodyCollision(t *testing.T) {
+	tree, _ := collision.NewTree()
+	w := NewWorld(tree)
+	defer w.Stop()
+	a := newTestBody(t, w, 0, 0)
+	b := newTestBody(t, w, 20, 0)
+	b.SetMass(3)
+	a.Restitution = 1
+	a.Velocity.SetX(4)
+	for i := 0; i < 10; i++ {
+		w.Step()
+	}
+	// Elastic collisions conserve momentum and energy
+	assert.InDelta(t, 4, a.Velocity.X()+3*b.Velocity.X(), .01)
+	assert.InDelta(t, -2, a.Velocity.X(), .01)
+	assert.InDelta(t, 2, b.Velocity.X(), .01)
+	assert.True(t, a.X()+10 <= b.X())
+
+	// Kinematic bodies push dynamic bodies without being pushed back
+	k := newTestBody(t, w, 0, 50)
+	k.Type = Kinematic
+	k.Velocity.SetX(2)
+	d := newTestBody(t, w, 15, 50)
+	for i := 0; i < 10; i++ {
+		w.Step()
+	}
+	assert.Equal(t, 2.0, k.Velocity.X())
+	assert.Equal(t, 20.0, k.X())
+	assert.True(t, d.X() >= 29)
+}
+
+func TestWorldSolidLabels(t *testing.T) {
+	tree, _ := collision.NewTree()
+	tree.Add(collision.NewLabeledSpace(0, 100, 200, 20, ground),
+		collision.NewLabeledSpace(0, 50, 200, 20, ground+1))
+	w := NewWorld(tree, ground)
+	defer w.Stop()
+	w.Gravity.SetPos(0, 1)
+	b := newTestBody(t, w, 50, 0)
+	for i := 0; i < 100; i++ {
+		w.Step()
+	}
+	assert.InDelta(t, 90, b.Y(), .1)
+}
+
+func TestWorldStart(t *testing.T) {
+	event.DefaultBus.SetSynchronous(true)
+	defer event.DefaultBus.SetSynchronous(false)
+
+	w, _, _ := newTestWorld(t)
+	b := newTestBody(t, w, 50, 0)
+	b.Velocity.SetX(1)
+	w.Start()
+	assert.Nil(t, event.Flush())
+	<-event.TriggerBack(event.Enter, 0)
+	assert.Equal(t, 51.0, b.X())
+	w.Stop()
+	<-event.TriggerBack(event.Enter, 0)
+	<-event.TriggerBack(event.Enter, 0)
+	assert.Equal(t, 51.0, b.X())
+}
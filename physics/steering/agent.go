@@ -0,0 +1,106 @@
+package steering
+
+import (
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/physics"
+)
+
+// An Agent is steered by behaviours. Its embedded vector is its position,
+// which is usually the vector of whatever it represents.
+type Agent struct {
+	physics.Vector
+	Velocity physics.Vector
+	// MaxSpeed is the greatest magnitude of the agent's velocity.
+	MaxSpeed float64
+	// MaxForce is the greatest magnitude of the combined force steering
+	// the agent each frame. If it is not positive, forces are not limited.
+	MaxForce float64
+}
+
+// NewAgent returns a still agent at v.
+func NewAgent(v physics.Vector, maxSpeed, maxForce float64) *Agent {
+	return &Agent{
+		Vector:   v,
+		Velocity: physics.NewVector(0, 0),
+		MaxSpeed: maxSpeed,
+		MaxForce: maxForce,
+	}
+}
+
+// A Behaviour returns the force with which it would steer an agent.
+type Behaviour interface {
+	Force(a *Agent) physics.Vector
+}
+
+// BehaviourFunc is a function which satisfies Behaviour.
+type BehaviourFunc func(a *Agent) physics.Vector
+
+// Force calls bf.
+func (bf BehaviourFunc) Force(a *Agent) physics.Vector {
+	return bf(a)
+}
+
+// Weighted scales the force of a behaviour by a weight.
+type Weighted struct {
+	Behaviour
+	Weight float64
+}
+
+// Weight returns b weighted by w.
+func Weight(b Behaviour, w float64) Weighted {
+	return Weighted{Behaviour: b, Weight: w}
+}
+
+// Steer returns the sum of the weighted forces of bs on the agent, limited
+// to MaxForce.
+func (a *Agent) Steer(bs ...Weighted) physics.Vector {
+	var sum floatgeom.Point2
+	for _, b := range bs {
+		sum = sum.Add(point(b.Force(a)).MulConst(b.Weight))
+	}
+	return vector(truncate(sum, a.MaxForce))
+}
+
+// Update adds the steering force of bs to the agent's velocity, limits it
+// to MaxSpeed, and returns a copy of it. The agent is not moved, so that
+// whatever it represents can be moved by the returned velocity.
+func (a *Agent) Update(bs ...Weighted) physics.Vector {
+	v := truncate(point(a.Velocity).Add(point(a.Steer(bs...))), a.MaxSpeed)
+	a.Velocity.SetPos(v.X(), v.Y())
+	return a.Velocity.Copy()
+}
+
+func (a *Agent) position() floatgeom.Point2 {
+	return point(a.Vector)
+}
+
+// heading returns the direction the agent is moving in, or right if it is
+// not moving.
+func (a *Agent) heading() floatgeom.Point2 {
+	v := point(a.Velocity)
+	if v.Magnitude() == 0 {
+		return floatgeom.Point2{1, 0}
+	}
+	return v.Normalize()
+}
+
+// toward returns the force steering the agent's velocity toward moving in
+// the direction of dir at speed.
+func (a *Agent) toward(dir floatgeom.Point2, speed float64) floatgeom.Point2 {
+	return dir.Normalize().MulConst(speed).Sub(point(a.Velocity))
+}
+
+func point(v physics.Vector) floatgeom.Point2 {
+	return floatgeom.Point2{v.X(), v.Y()}
+}
+
+func vector(p floatgeom.Point2) physics.Vector {
+	return physics.NewVector(p.X(), p.Y())
+}
+
+func truncate(p floatgeom.Point2, max float64) floatgeom.Point2 {
+	if max > 0 && p.Magnitude() > max {
+		return p.Normalize().MulConst(max)
+	}
+	return p
+}
@@ -0,0 +1,112 @@
+package steering
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/physics"
+)
+
+// DefaultAvoidNeighbors is how many of the nearest spaces are checked by
+// AvoidObstacles when its Neighbors is not positive.
+var DefaultAvoidNeighbors = 8
+
+// AvoidObstacles steers agents around the spaces of a collision tree which
+// are near and ahead of them, more strongly around closer spaces.
+type AvoidObstacles struct {
+	// Tree holds the obstacles. If it is nil, collision.DefTree is used.
+	Tree *collision.Tree
+	// Self is the agent's own space, if it has one. It is not avoided, and
+	// distances to obstacles are measured from its edges rather than from
+	// the agent's position.
+	Self *collision.Space
+	// Labels are the labels of spaces to avoid. If it is empty, every
+	// space is avoided.
+	Labels []collision.Label
+	// Distance is how close obstacles must be to be avoided.
+	Distance float64
+	// Neighbors is how many of the spaces nearest the agent are checked.
+	Neighbors int
+}
+
+// Force satisfies Behaviour.
+func (ao AvoidObstacles) Force(a *Agent) physics.Vector {
+	tree := ao.Tree
+	if tree == nil {
+		tree = collision.DefTree
+	}
+	k := ao.Neighbors
+	if k <= 0 {
+		k = DefaultAvoidNeighbors
+	}
+	from := a.position()
+	var self floatgeom.Rect2
+	if ao.Self != nil {
+		self = rect2(ao.Self.Location)
+		from = center(self)
+		// The agent's own space will be among the nearest
+		k++
+	} else {
+		self = floatgeom.Rect2{Min: from, Max: from}
+	}
+	heading := a.heading()
+
+	var force floatgeom.Point2
+	for _, sp := range tree.NearestNeighbors(k, floatgeom.Point3{from.X(), from.Y(), 0}) {
+		if sp == nil || sp == ao.Self || !ao.avoids(sp) {
+			continue
+		}
+		obstacle := rect2(sp.Location)
+		d := gap(self, obstacle)
+		if d >= ao.Distance {
+			continue
+		}
+		closest := floatgeom.Point2{
+			math.Max(obstacle.Min.X(), math.Min(from.X(), obstacle.Max.X())),
+			math.Max(obstacle.Min.Y(), math.Min(from.Y(), obstacle.Max.Y())),
+		}
+		if closest.Sub(from).Dot(heading) < 0 {
+			// Obstacles behind the agent are not in its way
+			continue
+		}
+		away := from.Sub(closest)
+		if away.Magnitude() == 0 {
+			away = from.Sub(center(obstacle))
+		}
+		// Steer to the side of the obstacle which the agent is already
+		// nearer, so that agents heading straight at obstacles go around
+		// them rather than stopping
+		side := floatgeom.Point2{-heading.Y(), heading.X()}
+		if from.Sub(center(obstacle)).Dot(side) < 0 {
+			side = side.MulConst(-1)
+		}
+		dir := away.Normalize().Add(side).Normalize()
+		strength := 1 - d/ao.Distance
+		force = force.Add(dir.MulConst(a.MaxSpeed * strength))
+	}
+	return vector(force)
+}
+
+func (ao AvoidObstacles) avoids(sp *collision.Space) bool {
+	return len(ao.Labels) == 0 || sp.HasLabel(ao.Labels...)
+}
+
+func rect2(r floatgeom.Rect3) floatgeom.Rect2 {
+	return floatgeom.Rect2{
+		Min: floatgeom.Point2{r.Min.X(), r.Min.Y()},
+		Max: floatgeom.Point2{r.Max.X(), r.Max.Y()},
+	}
+}
+
+// gap returns the distance between the nearest edges of two rectangles,
+// or zero if they overlap.
+func gap(a, b floatgeom.Rect2) float64 {
+	dx := math.Max(0, math.Max(b.Min.X()-a.Max.X(), a.Min.X()-b.Max.X()))
+	dy := math.Max(0, math.Max(b.Min.Y()-a.Max.Y(), a.Min.Y()-b.Max.Y()))
+	return math.Hypot(dx, dy)
+}
+
+func center(r floatgeom.Rect2) floatgeom.Point2 {
+	return floatgeom.Point2{r.Midpoint(0), r.Midpoint(1)}
+}
@@ -0,0 +1,142 @@
+package steering
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/oakmound/oak/alg"
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/physics"
+)
+
+// Seek steers agents toward a target at full speed.
+type Seek struct {
+	Target physics.Vector
+}
+
+// Force satisfies Behaviour.
+func (s Seek) Force(a *Agent) physics.Vector {
+	return vector(a.toward(point(s.Target).Sub(a.position()), a.MaxSpeed))
+}
+
+// Flee steers agents away from a target at full speed.
+type Flee struct {
+	Target physics.Vector
+	// Radius is how close the target must be to be fled from. If it is
+	// not positive, the target is always fled from.
+	Radius float64
+}
+
+// Force satisfies Behaviour.
+func (f Flee) Force(a *Agent) physics.Vector {
+	return vector(flee(a, point(f.Target), f.Radius))
+}
+
+func flee(a *Agent, target floatgeom.Point2, radius float64) floatgeom.Point2 {
+	away := a.position().Sub(target)
+	if radius > 0 && away.Magnitude() > radius {
+		return floatgeom.Point2{}
+	}
+	return a.toward(away, a.MaxSpeed)
+}
+
+// Arrive steers agents toward a target, slowing down as they near it so
+// that they stop on it.
+type Arrive struct {
+	Target physics.Vector
+	// SlowRadius is how close to the target agents begin to slow down.
+	SlowRadius float64
+}
+
+// Force satisfies Behaviour.
+func (ar Arrive) Force(a *Agent) physics.Vector {
+	to := point(ar.Target).Sub(a.position())
+	speed := a.MaxSpeed
+	if d := to.Magnitude(); d < ar.SlowRadius {
+		speed *= d / ar.SlowRadius
+	}
+	return vector(a.toward(to, speed))
+}
+
+// Pursue steers agents toward where another agent will be when they reach
+// it, if it keeps its velocity.
+type Pursue struct {
+	Target *Agent
+}
+
+// Force satisfies Behaviour.
+func (p Pursue) Force(a *Agent) physics.Vector {
+	return vector(a.toward(predict(a, p.Target).Sub(a.position()), a.MaxSpeed))
+}
+
+// Evade steers agents away from where another agent will be when it
+// reaches them, if it keeps its velocity.
+type Evade struct {
+	Target *Agent
+	// Radius is how close the target must be to be evaded. If it is not
+	// positive, the target is always evaded.
+	Radius float64
+}
+
+// Force satisfies Behaviour.
+func (e Evade) Force(a *Agent) physics.Vector {
+	if e.Radius > 0 && a.position().Distance(e.Target.position()) > e.Radius {
+		return physics.NewVector(0, 0)
+	}
+	return vector(flee(a, predict(a, e.Target), 0))
+}
+
+// predict returns where target will be when it and a would meet, if both
+// moved toward each other at full speed.
+func predict(a, target *Agent) floatgeom.Point2 {
+	to := target.position()
+	speed := a.MaxSpeed + point(target.Velocity).Magnitude()
+	if speed == 0 {
+		return to
+	}
+	frames := to.Distance(a.position()) / speed
+	return to.Add(point(target.Velocity).MulConst(frames))
+}
+
+// Wander steers agents randomly but smoothly, toward a point on a circle
+// ahead of them which moves a little each frame.
+type Wander struct {
+	// Distance is how far ahead of agents the circle is.
+	Distance float64
+	// Radius is the radius of the circle.
+	Radius float64
+	// Jitter is the most the point can move around the circle each frame,
+	// in degrees.
+	Jitter float64
+	// Rand is used to move the point. If it is nil, the global source of
+	// math/rand is used.
+	Rand *rand.Rand
+
+	angle float64
+}
+
+// NewWander returns a wander behaviour. Wander behaviours keep track of
+// their point, so each agent should have its own.
+func NewWander(distance, radius, jitter float64) *Wander {
+	return &Wander{
+		Distance: distance,
+		Radius:   radius,
+		Jitter:   jitter,
+	}
+}
+
+// Force satisfies Behaviour.
+func (w *Wander) Force(a *Agent) physics.Vector {
+	f := rand.Float64
+	if w.Rand != nil {
+		f = w.Rand.Float64
+	}
+	w.angle = math.Mod(w.angle+(f()*2-1)*w.Jitter, 360)
+	heading := a.heading()
+	angle := (heading.ToAngle() + w.angle) * alg.DegToRad
+	target := heading.MulConst(w.Distance).Add(floatgeom.Point2{
+		math.Cos(angle) * w.Radius,
+		math.Sin(angle) * w.Radius,
+	})
+	return vector(a.toward(target, a.MaxSpeed))
+}
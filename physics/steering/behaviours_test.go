@@ -0,0 +1,150 @@
+package steering
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/physics"
+	"github.com/stretchr/testify/assert"
+)
+
+// run updates and moves a for frames frames.
+func run(a *Agent, frames int, bs ...Weighted) {
+	for i := 0; i < frames; i++ {
+		v := a.Update(bs...)
+		a.Vector.Add(v)
+	}
+}
+
+func TestSteer(t *testing.T) {
+	a := NewAgent(physics.NewVector(0, 0), 2, 1)
+	target := physics.NewVector(100, 0)
+	f := a.Steer(Weight(Seek{Target: target}, 1))
+	assert.Equal(t, physics.NewVector(1, 0), f)
+
+	// Forces are weighted and summed
+	a.MaxForce = 0
+	f = a.Steer(Weight(Seek{Target: target}, 2), Weight(Flee{Target: target}, 1))
+	assert.Equal(t, physics.NewVector(2, 0), f)
+	f = a.Steer(Weight(BehaviourFunc(func(*Agent) physics.Vector {
+		return physics.NewVector(0, 3)
+	}), .5))
+	assert.Equal(t, physics.NewVector(0, 1.5), f)
+
+	// Velocity is limited to MaxSpeed, and agents are not moved
+	a.MaxForce = 1
+	for i := 0; i < 5; i++ {
+		a.Update(Weight(Seek{Target: target}, 1))
+	}
+	assert.Equal(t, physics.NewVector(2, 0), a.Velocity)
+	assert.Equal(t, physics.NewVector(0, 0), a.Vector)
+}
+
+func TestSeekFleeArrive(t *testing.T) {
+	a := NewAgent(physics.NewVector(0, 0), 2, .5)
+	target := physics.NewVector(50, 50)
+	run(a, 100, Weight(Arrive{Target: target, SlowRadius: 30}, 1))
+	assert.InDelta(t, 50, a.X(), .5)
+	assert.InDelta(t, 50, a.Y(), .5)
+	assert.InDelta(t, 0, a.Velocity.Magnitude(), .1)
+
+	// Fleeing agents stop fleeing outside of the radius
+	flee := Weight(Flee{Target: target, Radius: 20}, 1)
+	run(a, 60, flee)
+	d := a.Distance(target)
+	assert.True(t, d > 20)
+	assert.Equal(t, physics.NewVector(0, 0), a.Steer(flee))
+
+	// Seeking agents pass through their target
+	a = NewAgent(physics.NewVector(0, 0), 2, .5)
+	passed := false
+	for i := 0; i < 40; i++ {
+		run(a, 1, Weight(Seek{Target: physics.NewVector(20, 0)}, 1))
+		passed = passed || a.X() > 21
+	}
+	assert.True(t, passed)
+}
+
+func TestPursueEvade(t *testing.T) {
+	target := NewAgent(physics.NewVector(100, 0), 1, 0)
+	target.Velocity.SetPos(0, 1)
+	a := NewAgent(physics.NewVector(0, 0), 2, 0)
+	// Pursuers head for where the target will be
+	f := a.Steer(Weight(Pursue{Target: target}, 1))
+	assert.True(t, f.X() > 0)
+	assert.True(t, f.Y() > 0)
+	caught := false
+	for i := 0; i < 120 && !caught; i++ {
+		run(a, 1, Weight(Pursue{Target: target}, 1))
+		target.Vector.Add(target.Velocity)
+		caught = a.Distance(target.Vector) < 2
+	}
+	assert.True(t, caught)
+
+	evade := Weight(Evade{Target: target, Radius: 10}, 1)
+	f = a.Steer(evade)
+	assert.True(t, f.Magnitude() > 0)
+	run(a, 30, evade)
+	assert.True(t, a.Distance(target.Vector) > 10)
+}
+
+func TestWander(t *testing.T) {
+	a := NewAgent(physics.NewVector(0, 0), 2, .2)
+	w := NewWander(20, 10, 15)
+	w.Rand = rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		f := a.Steer(Weight(w, 1))
+		assert.True(t, f.Magnitude() <= .2+1e-9)
+		run(a, 1, Weight(w, 1))
+	}
+	assert.True(t, a.Velocity.Magnitude() > 1)
+	assert.True(t, a.Magnitude() > 0)
+}
+
+func TestAvoidObstacles(t *testing.T) {
+	tree, err := collision.NewTree()
+	assert.Nil(t, err)
+	wall := collision.NewLabeledSpace(50, -10, 20, 20, 1)
+	ignored := collision.NewLabeledSpace(20, -5, 5, 10, 2)
+	tree.Add(wall, ignored)
+	self := collision.NewUnassignedSpace(-2, -2, 4, 4)
+	tree.Add(self)
+
+	a := NewAgent(physics.NewVector(0, 0), 2, 1)
+	target := physics.NewVector(150, 0)
+	avoid := AvoidObstacles{
+		Tree:     tree,
+		Self:     self,
+		Labels:   []collision.Label{1},
+		Distance: 25,
+	}
+	hit := false
+	for i := 0; i < 150; i++ {
+		v := a.Update(Weight(Seek{Target: target}, 1), Weight(avoid, 3))
+		a.Vector.Add(v)
+		tree.ShiftSpace(v.X(), v.Y(), self)
+		if len(tree.Hit(self, func(sps []*collision.Space) []*collision.Space {
+			var out []*collision.Space
+			for _, sp := range sps {
+				if sp == wall {
+					out = append(out, sp)
+				}
+			}
+			return out
+		})) > 0 {
+			hit = true
+		}
+	}
+	assert.False(t, hit)
+	assert.True(t, a.X() > 70)
+
+	// Obstacles behind or far from agents are not avoided
+	a = NewAgent(physics.NewVector(100, 0), 2, 1)
+	a.Velocity.SetPos(1, 0)
+	avoid.Self = nil
+	assert.Equal(t, physics.NewVector(0, 0), a.Steer(Weight(avoid, 1)))
+	a.SetPos(0, 100)
+	a.Velocity.SetPos(1, 0)
+	assert.Equal(t, physics.NewVector(0, 0), a.Steer(Weight(avoid, 1)))
+}
@@ -0,0 +1,6 @@
+// Package steering provides steering behaviours, which move agents toward,
+// away from, around and alongside targets, obstacles and each other.
+//
+// Each frame, an agent combines the weighted forces of its behaviours into
+// a single steering force, which changes its velocity.
+package steering
@@ -0,0 +1,137 @@
+package steering
+
+import (
+	"sync"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/physics"
+)
+
+// A Group is a set of agents which flock, steering relative to the other
+// agents in the group near them.
+type Group struct {
+	sync.Mutex
+	agents []*Agent
+}
+
+// NewGroup returns a group of the given agents.
+func NewGroup(as ...*Agent) *Group {
+	return &Group{agents: as}
+}
+
+// Add adds agents to the group.
+func (g *Group) Add(as ...*Agent) {
+	g.Lock()
+	g.agents = append(g.agents, as...)
+	g.Unlock()
+}
+
+// Remove removes an agent from the group, returning whether it was in the
+// group.
+func (g *Group) Remove(a *Agent) bool {
+	g.Lock()
+	defer g.Unlock()
+	for i, a2 := range g.agents {
+		if a2 == a {
+			g.agents = append(g.agents[:i], g.agents[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Agents returns the agents in the group.
+func (g *Group) Agents() []*Agent {
+	g.Lock()
+	defer g.Unlock()
+	return append([]*Agent{}, g.agents...)
+}
+
+// neighbors returns the agents in the group other than a within radius of
+// it, or all of them if radius is not positive.
+func (g *Group) neighbors(a *Agent, radius float64) []*Agent {
+	g.Lock()
+	defer g.Unlock()
+	var out []*Agent
+	pos := a.position()
+	for _, a2 := range g.agents {
+		if a2 == a {
+			continue
+		}
+		if radius > 0 && a2.position().Distance(pos) > radius {
+			continue
+		}
+		out = append(out, a2)
+	}
+	return out
+}
+
+// Separation steers agents away from nearby agents in a group, more
+// strongly from closer agents.
+type Separation struct {
+	Group *Group
+	// Radius is how close agents must be to be steered away from. If it
+	// is not positive, every agent in the group is.
+	Radius float64
+}
+
+// Force satisfies Behaviour.
+func (s Separation) Force(a *Agent) physics.Vector {
+	var away floatgeom.Point2
+	pos := a.position()
+	for _, n := range s.Group.neighbors(a, s.Radius) {
+		diff := pos.Sub(n.position())
+		d := diff.Magnitude()
+		if d == 0 {
+			continue
+		}
+		away = away.Add(diff.DivConst(d * d))
+	}
+	if away.Magnitude() == 0 {
+		return physics.NewVector(0, 0)
+	}
+	return vector(a.toward(away, a.MaxSpeed))
+}
+
+// Alignment steers agents to move in the same direction as nearby agents
+// in a group.
+type Alignment struct {
+	Group *Group
+	// Radius is how close agents must be to be aligned with. If it is not
+	// positive, every agent in the group is.
+	Radius float64
+}
+
+// Force satisfies Behaviour.
+func (al Alignment) Force(a *Agent) physics.Vector {
+	var heading floatgeom.Point2
+	for _, n := range al.Group.neighbors(a, al.Radius) {
+		heading = heading.Add(point(n.Velocity))
+	}
+	if heading.Magnitude() == 0 {
+		return physics.NewVector(0, 0)
+	}
+	return vector(a.toward(heading, a.MaxSpeed))
+}
+
+// Cohesion steers agents toward the center of nearby agents in a group.
+type Cohesion struct {
+	Group *Group
+	// Radius is how close agents must be to be steered toward. If it is
+	// not positive, every agent in the group is.
+	Radius float64
+}
+
+// Force satisfies Behaviour.
+func (c Cohesion) Force(a *Agent) physics.Vector {
+	ns := c.Group.neighbors(a, c.Radius)
+	if len(ns) == 0 {
+		return physics.NewVector(0, 0)
+	}
+	var center floatgeom.Point2
+	for _, n := range ns {
+		center = center.Add(n.position())
+	}
+	center = center.DivConst(float64(len(ns)))
+	return vector(a.toward(center.Sub(a.position()), a.MaxSpeed))
+}
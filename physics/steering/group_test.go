@@ -0,0 +1,65 @@
+package steering
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/physics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	a := NewAgent(physics.NewVector(0, 0), 1, 0)
+	b := NewAgent(physics.NewVector(10, 0), 1, 0)
+	c := NewAgent(physics.NewVector(100, 0), 1, 0)
+	g := NewGroup(a, b)
+	g.Add(c)
+	assert.Equal(t, []*Agent{a, b, c}, g.Agents())
+	assert.Equal(t, []*Agent{b}, g.neighbors(a, 20))
+	assert.Equal(t, []*Agent{b, c}, g.neighbors(a, 0))
+	assert.True(t, g.Remove(c))
+	assert.False(t, g.Remove(c))
+	assert.Equal(t, []*Agent{a, b}, g.Agents())
+}
+
+func TestFlocking(t *testing.T) {
+	a := NewAgent(physics.NewVector(0, 0), 1, 0)
+	b := NewAgent(physics.NewVector(4, 0), 1, 0)
+	c := NewAgent(physics.NewVector(0, 40), 1, 0)
+	c.Velocity.SetPos(0, -1)
+	g := NewGroup(a, b, c)
+
+	f := a.Steer(Weight(Separation{Group: g, Radius: 10}, 1))
+	assert.Equal(t, physics.NewVector(-1, 0), f)
+	f = a.Steer(Weight(Cohesion{Group: g, Radius: 10}, 1))
+	assert.Equal(t, physics.NewVector(1, 0), f)
+	f = a.Steer(Weight(Alignment{Group: g}, 1))
+	assert.Equal(t, physics.NewVector(0, -1), f)
+	// Without nearby agents, there is no force
+	f = c.Steer(Weight(Separation{Group: g, Radius: 10}, 1),
+		Weight(Cohesion{Group: g, Radius: 10}, 1),
+		Weight(Alignment{Group: g, Radius: 10}, 1))
+	assert.Equal(t, physics.NewVector(0, 0), f)
+
+	// Flocks gather without overlapping
+	flock := []Weighted{
+		Weight(Separation{Group: g, Radius: 8}, 2),
+		Weight(Cohesion{Group: g}, 1),
+	}
+	c.Velocity.Zero()
+	for i := 0; i < 300; i++ {
+		for _, ag := range g.Agents() {
+			ag.MaxForce = .1
+			v := ag.Update(flock...)
+			ag.Vector.Add(v)
+		}
+	}
+	for _, ag := range g.Agents() {
+		for _, ag2 := range g.Agents() {
+			if ag != ag2 {
+				d := ag.Distance(ag2.Vector)
+				assert.True(t, d > 3, "agents too close: %v", d)
+				assert.True(t, d < 30, "agents too far: %v", d)
+			}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+// Package transform provides hierarchies of positions, rotations and
+// scales, where each node is placed relative to its parent, and renderables,
+// collision spaces and other positioned things can follow nodes.
+//
+// Unlike attaching vectors with physics.Vector.Attach, which only offsets
+// one position from another, nodes inherit the rotation and scale of their
+// parents.
+package transform
@@ -0,0 +1,299 @@
+package transform
+
+import (
+	"math"
+	"sync"
+
+	"github.com/oakmound/oak/alg/floatgeom"
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+)
+
+// A Graph is a set of hierarchies of nodes. Updating a graph propagates
+// the transforms of nodes to their children, parents before children.
+type Graph struct {
+	sync.Mutex
+	roots []*Node
+	stop  func()
+}
+
+// NewGraph returns an empty graph.
+func NewGraph() *Graph {
+	return &Graph{}
+}
+
+// A Node is placed relative to its parent by its local transform, or
+// relative to the world if it has no parent. Its world transform is found
+// when its graph is updated.
+type Node struct {
+	g        *Graph
+	parent   *Node
+	children []*Node
+	local    Transform
+	world    Transform
+	position physics.Vector
+	bindings []func(Transform)
+	dirty    bool
+	removed  bool
+}
+
+// NewNode returns a node at (x, y) with no parent, rotation or scaling.
+func (g *Graph) NewNode(x, y float64) *Node {
+	n := &Node{
+		g:        g,
+		local:    Identity,
+		world:    Identity,
+		position: physics.NewVector(0, 0),
+		dirty:    true,
+	}
+	n.local.Position = floatgeom.Point2{x, y}
+	g.Lock()
+	g.roots = append(g.roots, n)
+	g.Unlock()
+	return n
+}
+
+// Start updates the graph every frame, until it is stopped.
+func (g *Graph) Start() {
+	stop := event.EveryFrame(g.Update)
+	g.Lock()
+	g.stop = stop
+	g.Unlock()
+}
+
+// Stop stops the graph from being updated every frame.
+func (g *Graph) Stop() {
+	g.Lock()
+	stop := g.stop
+	g.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// Update finds the world transform of every node whose transform or
+// ancestors' transforms changed since the last update, and calls the
+// bindings of those nodes. Each node is updated once, after its parent.
+func (g *Graph) Update() {
+	g.Lock()
+	var changed []*Node
+	for _, n := range g.roots {
+		changed = n.propagate(Identity, false, changed)
+	}
+	bindings := make([][]func(Transform), len(changed))
+	worlds := make([]Transform, len(changed))
+	for i, n := range changed {
+		bindings[i] = append(bindings[i], n.bindings...)
+		worlds[i] = n.world
+	}
+	g.Unlock()
+	// Bindings are called without the graph locked, so they can change
+	// the graph, but changes to ancestors are only seen next update
+	for i, bs := range bindings {
+		for _, b := range bs {
+			b(worlds[i])
+		}
+	}
+}
+
+// propagate finds the world transforms of n and its descendants, and
+// appends each node whose world transform changed to changed.
+func (n *Node) propagate(parent Transform, parentChanged bool, changed []*Node) []*Node {
+	if n.dirty || parentChanged {
+		n.world = parent.Combine(n.local)
+		n.position.SetPos(n.world.Position.X(), n.world.Position.Y())
+		n.dirty = false
+		parentChanged = true
+		changed = append(changed, n)
+	}
+	for _, c := range n.children {
+		changed = c.propagate(n.world, parentChanged, changed)
+	}
+	return changed
+}
+
+// SetParent places n relative to p, or relative to the world if p is nil.
+// If p is n or one of n's descendants, n is not moved and a CyclicInput
+// error is returned.
+func (n *Node) SetParent(p *Node) error {
+	if p != nil && p.g != n.g {
+		return oakerr.InvalidInput{InputName: "p"}
+	}
+	n.g.Lock()
+	defer n.g.Unlock()
+	if n.removed || (p != nil && p.removed) {
+		return oakerr.NotFound{InputName: "n"}
+	}
+	for a := p; a != nil; a = a.parent {
+		if a == n {
+			return oakerr.CyclicInput{InputName: "p"}
+		}
+	}
+	n.detach()
+	n.parent = p
+	if p == nil {
+		n.g.roots = append(n.g.roots, n)
+	} else {
+		p.children = append(p.children, n)
+	}
+	n.dirty = true
+	return nil
+}
+
+// detach removes n from its parent's children, or from its graph's roots.
+func (n *Node) detach() {
+	siblings := &n.g.roots
+	if n.parent != nil {
+		siblings = &n.parent.children
+	}
+	for i, s := range *siblings {
+		if s == n {
+			*siblings = append((*siblings)[:i], (*siblings)[i+1:]...)
+			break
+		}
+	}
+	n.parent = nil
+}
+
+// Parent returns n's parent, or nil if it has none.
+func (n *Node) Parent() *Node {
+	n.g.Lock()
+	defer n.g.Unlock()
+	return n.parent
+}
+
+// Children returns the nodes placed relative to n.
+func (n *Node) Children() []*Node {
+	n.g.Lock()
+	defer n.g.Unlock()
+	return append([]*Node{}, n.children...)
+}
+
+// Remove removes n and its descendants from their graph. Removed nodes are
+// no longer updated.
+func (n *Node) Remove() {
+	n.g.Lock()
+	defer n.g.Unlock()
+	if n.removed {
+		return
+	}
+	n.detach()
+	n.markRemoved()
+}
+
+func (n *Node) markRemoved() {
+	n.removed = true
+	for _, c := range n.children {
+		c.markRemoved()
+	}
+}
+
+// Local returns n's transform relative to its parent.
+func (n *Node) Local() Transform {
+	n.g.Lock()
+	defer n.g.Unlock()
+	return n.local
+}
+
+// SetLocal sets n's transform relative to its parent.
+func (n *Node) SetLocal(t Transform) {
+	n.g.Lock()
+	n.local = t
+	n.dirty = true
+	n.g.Unlock()
+}
+
+// SetPos sets n's position relative to its parent.
+func (n *Node) SetPos(x, y float64) {
+	n.g.Lock()
+	n.local.Position = floatgeom.Point2{x, y}
+	n.dirty = true
+	n.g.Unlock()
+}
+
+// ShiftPos moves n's position relative to its parent by (x, y).
+func (n *Node) ShiftPos(x, y float64) {
+	n.g.Lock()
+	n.local.Position = n.local.Position.Add(floatgeom.Point2{x, y})
+	n.dirty = true
+	n.g.Unlock()
+}
+
+// SetRotation sets n's rotation relative to its parent, in degrees.
+func (n *Node) SetRotation(degrees float64) {
+	n.g.Lock()
+	n.local.Rotation = degrees
+	n.dirty = true
+	n.g.Unlock()
+}
+
+// Rotate rotates n relative to its parent by degrees.
+func (n *Node) Rotate(degrees float64) {
+	n.g.Lock()
+	n.local.Rotation += degrees
+	n.dirty = true
+	n.g.Unlock()
+}
+
+// SetScale sets n's scale relative to its parent.
+func (n *Node) SetScale(x, y float64) {
+	n.g.Lock()
+	n.local.Scale = floatgeom.Point2{x, y}
+	n.dirty = true
+	n.g.Unlock()
+}
+
+// World returns n's transform relative to the world as of the last update
+// of its graph.
+func (n *Node) World() Transform {
+	n.g.Lock()
+	defer n.g.Unlock()
+	return n.world
+}
+
+// Vector returns a vector which is kept at n's world position whenever its
+// graph is updated. Its pointers can be given to things which follow
+// positions through pointers, such as audio.New and audio.NewEars.
+func (n *Node) Vector() physics.Vector {
+	return n.position
+}
+
+// Bind calls f with n's world transform after each update of its graph in
+// which the transform changed, starting with the next update.
+func (n *Node) Bind(f func(Transform)) {
+	n.g.Lock()
+	n.bindings = append(n.bindings, f)
+	n.dirty = true
+	n.g.Unlock()
+}
+
+// A Positional can be moved to a position, as renderables and entities
+// can be.
+type Positional interface {
+	SetPos(x, y float64)
+}
+
+// BindPositional keeps p at n's world position offset by (x, y). The offset
+// is rotated and scaled with n.
+func (n *Node) BindPositional(p Positional, x, y float64) {
+	n.Bind(func(t Transform) {
+		pos := t.Apply(floatgeom.Point2{x, y})
+		p.SetPos(pos.X(), pos.Y())
+	})
+}
+
+// BindSpace keeps sp at n's world position in tree, or in collision.DefTree
+// if tree is nil. The size of sp is scaled by n's world scale from its
+// size when bound. Spaces are kept axis aligned, and are not rotated.
+func (n *Node) BindSpace(tree *collision.Tree, sp *collision.Space) {
+	if tree == nil {
+		tree = collision.DefTree
+	}
+	w, h := sp.GetW(), sp.GetH()
+	n.Bind(func(t Transform) {
+		tree.UpdateSpace(t.Position.X(), t.Position.Y(),
+			w*math.Abs(t.Scale.X()), h*math.Abs(t.Scale.Y()), sp)
+	})
+}
@@ -0,0 +1,142 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/physics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropagation(t *testing.T) {
+	g := NewGraph()
+	root := g.NewNode(100, 100)
+	arm := g.NewNode(10, 0)
+	hand := g.NewNode(5, 0)
+	assert.Nil(t, arm.SetParent(root))
+	assert.Nil(t, hand.SetParent(arm))
+	g.Update()
+	assert.InDelta(t, 115, hand.World().Position.X(), .0001)
+	assert.InDelta(t, 100, hand.World().Position.Y(), .0001)
+
+	// Children inherit rotation and scale
+	root.SetRotation(90)
+	root.SetScale(2, 2)
+	g.Update()
+	w := hand.World()
+	assert.InDelta(t, 100, w.Position.X(), .0001)
+	assert.InDelta(t, 130, w.Position.Y(), .0001)
+	assert.InDelta(t, 90, w.Rotation, .0001)
+	assert.InDelta(t, 2, w.Scale.X(), .0001)
+
+	arm.Rotate(-90)
+	g.Update()
+	w = hand.World()
+	assert.InDelta(t, 110, w.Position.X(), .0001)
+	assert.InDelta(t, 120, w.Position.Y(), .0001)
+	assert.InDelta(t, 0, w.Rotation, .0001)
+
+	// Vectors follow world positions
+	v := hand.Vector()
+	assert.InDelta(t, 110, v.X(), .0001)
+	assert.InDelta(t, 120, *v.Yp(), .0001)
+
+	// Nodes placed back at the root keep only their local transform
+	assert.Nil(t, arm.SetParent(nil))
+	g.Update()
+	assert.InDelta(t, 10, hand.World().Position.X(), .0001)
+	assert.Nil(t, arm.Parent())
+	assert.Equal(t, []*Node{hand}, arm.Children())
+	assert.Empty(t, root.Children())
+}
+
+func TestCycles(t *testing.T) {
+	g := NewGraph()
+	a := g.NewNode(0, 0)
+	b := g.NewNode(0, 0)
+	c := g.NewNode(0, 0)
+	assert.Nil(t, b.SetParent(a))
+	assert.Nil(t, c.SetParent(b))
+	assert.Equal(t, oakerr.CyclicInput{InputName: "p"}, a.SetParent(c))
+	assert.Equal(t, oakerr.CyclicInput{InputName: "p"}, a.SetParent(a))
+	assert.Nil(t, a.Parent())
+
+	other := NewGraph().NewNode(0, 0)
+	assert.Equal(t, oakerr.InvalidInput{InputName: "p"}, a.SetParent(other))
+
+	b.Remove()
+	assert.Empty(t, a.Children())
+	assert.Equal(t, oakerr.NotFound{InputName: "n"}, c.SetParent(a))
+}
+
+type positioned struct {
+	x, y float64
+}
+
+func (p *positioned) SetPos(x, y float64) {
+	p.x, p.y = x, y
+}
+
+func TestBindings(t *testing.T) {
+	g := NewGraph()
+	root := g.NewNode(50, 50)
+	child := g.NewNode(10, 0)
+	assert.Nil(t, child.SetParent(root))
+
+	p := &positioned{}
+	child.BindPositional(p, 0, 5)
+	tree, err := collision.NewTree()
+	assert.Nil(t, err)
+	sp := collision.NewUnassignedSpace(0, 0, 4, 6)
+	tree.Add(sp)
+	child.BindSpace(tree, sp)
+	calls := 0
+	root.Bind(func(Transform) {
+		calls++
+	})
+
+	g.Update()
+	assert.Equal(t, 60.0, p.x)
+	assert.Equal(t, 55.0, p.y)
+	assert.Equal(t, 60.0, sp.X())
+	assert.Equal(t, 50.0, sp.Y())
+	assert.Equal(t, 1, calls)
+
+	// Bindings are only called when transforms change
+	g.Update()
+	assert.Equal(t, 1, calls)
+
+	root.SetScale(2, -1)
+	g.Update()
+	assert.Equal(t, 70.0, p.x)
+	assert.Equal(t, 45.0, p.y)
+	assert.Equal(t, 8.0, sp.GetW())
+	assert.Equal(t, 6.0, sp.GetH())
+	assert.Equal(t, 2, calls)
+
+	// Removed nodes are not updated
+	root.Remove()
+	root.ShiftPos(10, 0)
+	g.Update()
+	assert.Equal(t, 70.0, p.x)
+	assert.Equal(t, 2, calls)
+}
+
+func TestGraphStart(t *testing.T) {
+	event.DefaultBus.SetSynchronous(true)
+	defer event.DefaultBus.SetSynchronous(false)
+	g := NewGraph()
+	n := g.NewNode(0, 0)
+	g.Start()
+	assert.Nil(t, event.Flush())
+	n.SetPos(3, 4)
+	<-event.TriggerBack(event.Enter, 0)
+	assert.Equal(t, physics.NewVector(3, 4), n.Vector())
+	g.Stop()
+	n.SetPos(5, 6)
+	<-event.TriggerBack(event.Enter, 0)
+	<-event.TriggerBack(event.Enter, 0)
+	assert.Equal(t, 3.0, n.Vector().X())
+}
@@ -0,0 +1,44 @@
+package transform
+
+import (
+	"math"
+
+	"github.com/oakmound/oak/alg"
+	"github.com/oakmound/oak/alg/floatgeom"
+)
+
+// A Transform places something relative to a parent: it is scaled, then
+// rotated, then moved to Position.
+type Transform struct {
+	Position floatgeom.Point2
+	// Rotation is in degrees, and rotates as physics.Vector.Rotate does.
+	Rotation float64
+	Scale    floatgeom.Point2
+}
+
+// Identity is the transform which places things exactly where their
+// parent is.
+var Identity = Transform{Scale: floatgeom.Point2{1, 1}}
+
+// Apply returns where p, relative to something placed by t, is relative to
+// t's parent.
+func (t Transform) Apply(p floatgeom.Point2) floatgeom.Point2 {
+	p = floatgeom.Point2{p.X() * t.Scale.X(), p.Y() * t.Scale.Y()}
+	sin, cos := math.Sincos(t.Rotation * alg.DegToRad)
+	return floatgeom.Point2{
+		p.X()*cos - p.Y()*sin + t.Position.X(),
+		p.X()*sin + p.Y()*cos + t.Position.Y(),
+	}
+}
+
+// Combine returns the transform which places child relative to t's parent,
+// where child is relative to something placed by t. Scales are combined
+// per axis before rotation, so a child of a rotated parent which is not
+// scaled evenly is not skewed as it would be by a matrix.
+func (t Transform) Combine(child Transform) Transform {
+	return Transform{
+		Position: t.Apply(child.Position),
+		Rotation: t.Rotation + child.Rotation,
+		Scale:    floatgeom.Point2{t.Scale.X() * child.Scale.X(), t.Scale.Y() * child.Scale.Y()},
+	}
+}
@@ -0,0 +1,278 @@
+package oak
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/oakmound/oak/audio"
+	"github.com/oakmound/oak/dlog"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/oakerr"
+	"github.com/oakmound/oak/render"
+	"github.com/oakmound/oak/scene"
+)
+
+// LoadProgress is the payload of event.AssetLoadProgress, reporting how
+// many assets of a scene's manifest have been loaded so far.
+type LoadProgress struct {
+	Scene  string
+	Loaded int
+	Total  int
+}
+
+func init() {
+	event.RegisterPayload(event.AssetLoadProgress, LoadProgress{})
+}
+
+type assetKind int
+
+const (
+	spriteAsset assetKind = iota
+	sheetAsset
+	audioAsset
+	fontAsset
+)
+
+// A manifestAsset identifies an asset loaded through a scene manifest.
+type manifestAsset struct {
+	kind assetKind
+	name string
+}
+
+// A preload tracks the loading of a single scene's manifest.
+type preload struct {
+	done chan struct{}
+	// cancel is closed when the manifest is no longer needed, to stop
+	// loading any assets that have not yet been loaded.
+	cancel chan struct{}
+	loaded int
+	total  int
+}
+
+var (
+	preloadLock sync.Mutex
+	// preloads holds manifests that are loading or loaded, by scene name.
+	preloads = map[string]*preload{}
+	// manifestAssets holds assets which were loaded through manifests, and
+	// which will be unloaded once no scene's manifest references them.
+	// Assets which were already loaded by other means are not included.
+	manifestAssets = map[manifestAsset]bool{}
+)
+
+// PreloadScene begins loading the manifest of the named scene in the
+// background. Progress is reported through event.AssetLoadProgress. If the
+// manifest is already loading or loaded this does nothing.
+func PreloadScene(name string) error {
+	scen, ok := SceneMap.Get(name)
+	if !ok {
+		return oakerr.NotFound{InputName: name}
+	}
+	startPreload(name, scen.Manifest)
+	return nil
+}
+
+// SceneLoaded returns whether the manifest of the named scene has been
+// completely loaded.
+func SceneLoaded(name string) bool {
+	preloadLock.Lock()
+	p, ok := preloads[name]
+	preloadLock.Unlock()
+	if !ok {
+		return false
+	}
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func startPreload(name string, m *scene.Manifest) *preload {
+	preloadLock.Lock()
+	p, ok := preloads[name]
+	if !ok {
+		p = &preload{
+			done:   make(chan struct{}),
+			cancel: make(chan struct{}),
+			total:  m.Len(),
+		}
+		preloads[name] = p
+		go p.load(name, m)
+	}
+	preloadLock.Unlock()
+	return p
+}
+
+func (p *preload) load(name string, m *scene.Manifest) {
+	defer close(p.done)
+	if m == nil {
+		return
+	}
+	audioDir := assetDir(conf.Assets.AudioPath)
+	for _, f := range m.Sprites {
+		if p.cancelled() {
+			return
+		}
+		loaded := render.SpriteIsLoaded(f)
+		_, err := render.LoadSprite("", f)
+		p.progress(name, err, loaded, manifestAsset{spriteAsset, f})
+	}
+	for _, sh := range m.Sheets {
+		if p.cancelled() {
+			return
+		}
+		loaded := render.SheetIsLoaded(sh.File)
+		var err error
+		if !loaded {
+			spriteLoaded := render.SpriteIsLoaded(sh.File)
+			_, err = render.LoadSheet("", sh.File, sh.W, sh.H, sh.Pad)
+			// Loading a sheet also loads the sprite it is split from
+			if err == nil && !spriteLoaded {
+				p.track(manifestAsset{spriteAsset, sh.File})
+			}
+		}
+		p.progress(name, err, loaded, manifestAsset{sheetAsset, sh.File})
+	}
+	for _, f := range m.Audio {
+		if p.cancelled() {
+			return
+		}
+		loaded := audio.IsLoaded(f)
+		_, err := audio.Load(audioDir, f)
+		p.progress(name, err, loaded, manifestAsset{audioAsset, f})
+	}
+	for _, f := range m.Fonts {
+		if p.cancelled() {
+			return
+		}
+		loaded := render.FontIsLoaded(f)
+		var err error
+		if render.LoadFont("", f) == nil {
+			err = oakerr.NotFound{InputName: f}
+		}
+		p.progress(name, err, loaded, manifestAsset{fontAsset, f})
+	}
+}
+
+// cancelled returns whether the preload should stop loading assets.
+func (p *preload) cancelled() bool {
+	select {
+	case <-p.cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *preload) track(a manifestAsset) {
+	preloadLock.Lock()
+	manifestAssets[a] = true
+	preloadLock.Unlock()
+}
+
+// progress records that an asset has been attempted and reports how many
+// assets have been loaded so far. Assets that failed to load still count
+// toward progress, so that a loading scene waiting on them will end.
+func (p *preload) progress(name string, err error, wasLoaded bool, a manifestAsset) {
+	preloadLock.Lock()
+	if err != nil {
+		dlog.Error("Failed to load", a.name, "for scene", name, err)
+	} else if !wasLoaded {
+		manifestAssets[a] = true
+	}
+	p.loaded++
+	lp := LoadProgress{
+		Scene:  name,
+		Loaded: p.loaded,
+		Total:  p.total,
+	}
+	preloadLock.Unlock()
+	logicHandler.Trigger(event.AssetLoadProgress, lp)
+}
+
+// loadManifest loads the manifest of the named scene, waiting on a preload
+// already in progress if there is one, then unloads assets which are no
+// longer referenced.
+func loadManifest(name string, m *scene.Manifest) {
+	if m != nil {
+		<-startPreload(name, m).done
+	}
+	unloadUnreferenced()
+}
+
+// preloadManifests begins loading the manifests of every scene the
+// given manifest asks to preload.
+func preloadManifests(m *scene.Manifest) {
+	if m == nil || len(m.Preload) == 0 {
+		return
+	}
+	// Bindings made as the scene started should see every progress event
+	dlog.ErrorCheck(logicHandler.Flush())
+	for _, name := range m.Preload {
+		dlog.ErrorCheck(PreloadScene(name))
+	}
+}
+
+// unloadUnreferenced unloads every asset loaded through a manifest which is
+// not declared by the current scene or a suspended scene, and stops
+// preloading the manifests of other scenes. Assets those preloads were
+// loading as they stopped are unloaded the next time this is called.
+func unloadUnreferenced() {
+	names := append(SuspendedScenes(), SceneMap.CurrentScene)
+	referenced := map[manifestAsset]bool{}
+	kept := map[string]bool{}
+	for _, name := range names {
+		kept[name] = true
+		scen, ok := SceneMap.Get(name)
+		if !ok || scen.Manifest == nil {
+			continue
+		}
+		m := scen.Manifest
+		for _, f := range m.Sprites {
+			referenced[manifestAsset{spriteAsset, f}] = true
+		}
+		for _, sh := range m.Sheets {
+			referenced[manifestAsset{sheetAsset, sh.File}] = true
+			referenced[manifestAsset{spriteAsset, sh.File}] = true
+		}
+		for _, f := range m.Audio {
+			referenced[manifestAsset{audioAsset, f}] = true
+		}
+		for _, f := range m.Fonts {
+			referenced[manifestAsset{fontAsset, f}] = true
+		}
+	}
+
+	preloadLock.Lock()
+	defer preloadLock.Unlock()
+	for name, p := range preloads {
+		if !kept[name] {
+			close(p.cancel)
+			delete(preloads, name)
+		}
+	}
+	for a := range manifestAssets {
+		if referenced[a] {
+			continue
+		}
+		dlog.Verb("Unloading", a.name)
+		switch a.kind {
+		case spriteAsset:
+			render.UnloadSprite(a.name)
+		case sheetAsset:
+			render.UnloadSheet(a.name)
+		case audioAsset:
+			audio.Unload(a.name)
+		case fontAsset:
+			render.UnloadFont(a.name)
+		}
+		delete(manifestAssets, a)
+	}
+}
+
+func assetDir(path string) string {
+	wd, _ := os.Getwd()
+	return filepath.Join(wd, conf.Assets.AssetPath, path)
+}
@@ -0,0 +1,103 @@
+package oak
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/render"
+	"github.com/oakmound/oak/scene"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScenePreload(t *testing.T) {
+	resetOak()
+	testinit()
+
+	font := "../../render/default_assets/font/luxisr.ttf"
+
+	var progressLock sync.Mutex
+	var progress []LoadProgress
+	AddScene("preloadFirst", scene.Scene{
+		Start: func(string, interface{}) {
+			event.GlobalBind(func(_ int, data interface{}) int {
+				progressLock.Lock()
+				progress = append(progress, data.(LoadProgress))
+				progressLock.Unlock()
+				return 0
+			}, event.AssetLoadProgress)
+		},
+		Loop: func() bool { return true },
+		End: func() (string, *scene.Result) {
+			return "preloadSecond", nil
+		},
+		Manifest: &scene.Manifest{
+			Preload: []string{"preloadSecond"},
+		},
+	})
+	AddScene("preloadSecond", scene.Scene{
+		Start: func(string, interface{}) {},
+		Loop:  func() bool { return true },
+		End: func() (string, *scene.Result) {
+			return "blank", nil
+		},
+		Manifest: &scene.Manifest{
+			Fonts: []string{font},
+			// Assets which fail to load still count towards progress
+			Sprites: []string{"missing.png"},
+		},
+	})
+
+	assert.NotNil(t, PreloadScene("preloadMissing"))
+
+	testNextScene <- "preloadFirst"
+	skipSceneCh <- true
+	sleep()
+	assert.Equal(t, "preloadFirst", SceneMap.CurrentScene)
+	assert.True(t, SceneLoaded("preloadSecond"))
+	assert.True(t, render.FontIsLoaded(font))
+	progressLock.Lock()
+	// Triggers are not ordered, so we only check the final event was seen
+	assert.Len(t, progress, 2)
+	assert.Contains(t, progress, LoadProgress{"preloadSecond", 2, 2})
+	progressLock.Unlock()
+
+	skipSceneCh <- true
+	sleep()
+	assert.Equal(t, "preloadSecond", SceneMap.CurrentScene)
+	assert.True(t, render.FontIsLoaded(font))
+
+	// The blank scene does not reference the font, so it is unloaded
+	skipSceneCh <- true
+	sleep()
+	assert.False(t, SceneLoaded("preloadSecond"))
+	assert.False(t, render.FontIsLoaded(font))
+}
+
+func TestPreloadCancel(t *testing.T) {
+	resetOak()
+	testinit()
+
+	m := &scene.Manifest{
+		Sprites: []string{"a.png", "b.png"},
+		Fonts:   []string{"a.ttf"},
+	}
+	p := &preload{
+		done:   make(chan struct{}),
+		cancel: make(chan struct{}),
+		total:  m.Len(),
+	}
+	preloadLock.Lock()
+	preloads["preloadCancelled"] = p
+	preloadLock.Unlock()
+
+	// Preloads of scenes which are not kept are cancelled
+	unloadUnreferenced()
+	assert.True(t, p.cancelled())
+	assert.False(t, SceneLoaded("preloadCancelled"))
+
+	// Cancelled preloads load nothing further
+	p.load("preloadCancelled", m)
+	assert.Equal(t, 0, p.loaded)
+	<-p.done
+}
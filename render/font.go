@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/golang/freetype/truetype"
 	"golang.org/x/image/colornames"
@@ -29,6 +30,7 @@ var (
 	DefFontGenerator = FontGenerator{}
 
 	loadedFonts = make(map[string]*truetype.Font)
+	fontLock    = sync.Mutex{}
 )
 
 // A FontGenerator stores information that can be used to create a font
@@ -179,8 +181,14 @@ func FontColor(s string) image.Image {
 
 // LoadFont loads in a font file and stores it with the given fontFile name.
 // This is necessary before using that file in a generator, otherwise the default
-// directory will be tried at generation time.
+// directory will be tried at generation time. If the empty string is passed
+// in for dir, the directory defined by oak.SetupConfig.Assets.Font will be used.
 func LoadFont(dir string, fontFile string) *truetype.Font {
+	if dir == "" {
+		dir = fontdir
+	}
+	fontLock.Lock()
+	defer fontLock.Unlock()
 	if _, ok := loadedFonts[fontFile]; !ok {
 		fontBytes, err := fileutil.ReadFile(filepath.Join(dir, fontFile))
 		if err != nil {
@@ -196,3 +204,20 @@ func LoadFont(dir string, fontFile string) *truetype.Font {
 	}
 	return loadedFonts[fontFile]
 }
+
+// FontIsLoaded returns whether, when LoadFont is called, a cached font will
+// be used, or if false that a new file will attempt to be loaded and stored
+func FontIsLoaded(fontFile string) bool {
+	fontLock.Lock()
+	_, ok := loadedFonts[fontFile]
+	fontLock.Unlock()
+	return ok
+}
+
+// UnloadFont removes the given font file from the set of loaded fonts.
+// Fonts already generated from that file are unaffected.
+func UnloadFont(fontFile string) {
+	fontLock.Lock()
+	delete(loadedFonts, fontFile)
+	fontLock.Unlock()
+}
@@ -40,6 +40,22 @@ func SetAssetPaths(imagedir string) {
 	dir = imagedir
 }
 
+// UnloadSprite removes the given file from the set of loaded sprites.
+// Sprites already created from that file are unaffected.
+func UnloadSprite(fileName string) {
+	imageLock.Lock()
+	delete(loadedImages, fileName)
+	imageLock.Unlock()
+}
+
+// UnloadSheet removes the given file from the set of loaded sheets.
+// The sprite the sheet was split from remains loaded.
+func UnloadSheet(fileName string) {
+	sheetLock.Lock()
+	delete(loadedSheets, fileName)
+	sheetLock.Unlock()
+}
+
 // UnloadAll resets the cached set of loaded sprites and sheets to empty.
 func UnloadAll() {
 	imageLock.Lock()
@@ -24,3 +24,21 @@ func TestBatchLoad(t *testing.T) {
 	assert.NotNil(t, sp)
 	assert.Nil(t, err)
 }
+
+func TestUnload(t *testing.T) {
+	fileutil.BindataDir = AssetDir
+	fileutil.BindataFn = Asset
+	assert.Nil(t, BatchLoad(filepath.Join("assets", "images")))
+	file := filepath.Join("16", "jeremy.png")
+	assert.True(t, SheetIsLoaded(file))
+	UnloadSheet(file)
+	assert.False(t, SheetIsLoaded(file))
+	assert.True(t, SpriteIsLoaded(file))
+	UnloadSprite(file)
+	assert.False(t, SpriteIsLoaded(file))
+
+	LoadFont(filepath.Join("default_assets", "font"), "luxisr.ttf")
+	assert.True(t, FontIsLoaded("luxisr.ttf"))
+	UnloadFont("luxisr.ttf")
+	assert.False(t, FontIsLoaded("luxisr.ttf"))
+}
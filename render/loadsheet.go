@@ -9,9 +9,15 @@ import (
 
 // LoadSheet loads a file in some directory with sheets of (w,h) sized sprites,
 // where there is pad pixels of vertical/horizontal pad between each sprite.
-// This will blow away any cached sheet with the same fileName.
+// This will blow away any cached sheet with the same fileName. If the empty
+// string is passed in for directory, the directory defined by
+// oak.SetupConfig.Assets.Images will be used.
 func LoadSheet(directory, fileName string, w, h, pad int) (*Sheet, error) {
 
+	if directory == "" {
+		directory = dir
+	}
+
 	if w <= 0 {
 		dlog.Error("Bad dimensions given to load sheet")
 		return nil, oakerr.InvalidInput{InputName: "w"}
@@ -55,6 +55,12 @@ func (pg *Polygon) UpdatePoints(points ...floatgeom.Point2) error {
 	return nil
 }
 
+// Points returns a copy of the points of this polygon, in order. Use
+// UpdatePoints to change them.
+func (pg *Polygon) Points() []floatgeom.Point2 {
+	return append([]floatgeom.Point2{}, pg.points...)
+}
+
 // Fill fills the inside of this polygon with the input color
 func (pg *Polygon) Fill(c color.Color) {
 	// Reset the rgba of the polygon
@@ -17,6 +17,10 @@ func TestContains(t *testing.T) {
 		floatgeom.Point2{10, 20},
 	)
 	assert.Nil(t, err)
+	assert.Equal(t, floatgeom.Point2{20, 10}, p.Points()[1])
+	// Changing returned points does not change the polygon
+	p.Points()[1] = floatgeom.Point2{40, 40}
+	assert.Equal(t, floatgeom.Point2{20, 10}, p.Points()[1])
 	assert.True(t, p.Contains(11, 11))
 	assert.False(t, p.Contains(16, 16))
 	assert.False(t, p.Contains(40, 40))
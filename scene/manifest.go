@@ -0,0 +1,32 @@
+package scene
+
+// A Manifest declares the assets a scene depends on. Scenes with a manifest
+// will have its assets loaded before they start, and assets loaded through
+// manifests are unloaded once no running or suspended scene declares them.
+//
+// Sprites, Sheets and Fonts are relative to the configured image and font
+// directories, Audio is relative to the configured audio directory.
+type Manifest struct {
+	Sprites []string
+	Sheets  []Sheet
+	Audio   []string
+	Fonts   []string
+	// Preload lists scenes whose manifests should be loaded in the
+	// background once this scene has started.
+	Preload []string
+}
+
+// A Sheet is a sprite sheet in a manifest, split into W by H sized sprites
+// with Pad pixels between each sprite.
+type Sheet struct {
+	File      string
+	W, H, Pad int
+}
+
+// Len returns the number of assets in the manifest.
+func (m *Manifest) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.Sprites) + len(m.Sheets) + len(m.Audio) + len(m.Fonts)
+}
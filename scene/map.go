@@ -41,7 +41,7 @@ func (m *Map) GetCurrent() (Scene, bool) {
 // Add adds a scene with the given name and functions to the scene map.
 // It serves as a helper for not constructing a scene directly.
 func (m *Map) Add(name string, start Start, loop Loop, end End) error {
-	return m.AddScene(name, Scene{start, loop, end})
+	return m.AddScene(name, Scene{Start: start, Loop: loop, End: end})
 }
 
 // AddScene takes a scene struct, checks that its assigned name does not
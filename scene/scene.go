@@ -1,11 +1,18 @@
 package scene
 
 // A Scene is a set of functions defining what needs to happen when a scene
-// starts, loops, and ends.
+// starts, loops, and ends. Resume is optional, and is called in place of
+// Start when a scene which was suspended by a pushed scene resumes. Leave
+// is optional, and decides how the scene is left; without it, scenes are
+// ended and the engine is reset. Manifest is optional, and declares the
+// assets the scene depends on.
 type Scene struct {
 	Start
 	Loop
 	End
+	Resume
+	Leave
+	Manifest *Manifest
 }
 
 // A Result is a set of options for what should be passed into the next
@@ -15,6 +22,43 @@ type Result struct {
 	Transition
 }
 
+// An Exit is how a scene is left.
+//
+// If Push is set, the scene is suspended instead of ended: its bindings,
+// entities, collision spaces and draw stack are kept frozen, and drawn
+// beneath, while the next scene runs on top of it. If Pop is set, the scene
+// is ended and the most recently suspended scene is resumed in place of
+// whatever next scene End returns.
+//
+// Push and Pop cannot both be set. An exit with both set ends the scene as
+// if neither was set.
+//
+// Persist marks portions of the engine which should carry over into the next
+// scene instead of being reset. It is ignored if Push or Pop is set.
+type Exit struct {
+	Push bool
+	Pop  bool
+	Persist
+}
+
+// Persist is a set of portions of the engine which are normally reset when a
+// scene ends. Persisting bindings, collision spaces or renderables without
+// also persisting entities will leave them referring to caller ids which may
+// be reassigned to new entities.
+type Persist struct {
+	// Bindings keeps all bindings on the logic handler.
+	Bindings bool
+	// Entities keeps all entities and their caller ids, new entities will
+	// be given ids after those of persisted entities.
+	Entities bool
+	// Collision keeps all spaces in the default collision tree.
+	Collision bool
+	// MouseCollision keeps all spaces in the default mouse collision tree.
+	MouseCollision bool
+	// DrawStack keeps everything drawn to the global draw stack.
+	DrawStack bool
+}
+
 // Start is a function taking in a previous scene and a payload
 // of data from the previous scene's end.
 type Start func(prevScene string, data interface{})
@@ -24,5 +68,14 @@ type Start func(prevScene string, data interface{})
 type Loop func() bool
 
 // End is a function returning the next scene and a SceneResult of
-// input settings for the next scene.
+// input settings for the next scene. End is called after the engine is
+// reset, so anything it creates carries into the next scene.
 type End func() (string, *Result)
+
+// Leave is a function deciding how a scene is left. It is called when
+// the scene's loop ends, before the engine is reset and End is called.
+type Leave func() Exit
+
+// Resume is a function taking in the scene which was popped to resume
+// a suspended scene, and a payload of data from the popped scene's end.
+type Resume func(prevScene string, data interface{})
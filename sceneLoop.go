@@ -2,6 +2,7 @@ package oak
 
 import (
 	"image"
+	"sync/atomic"
 
 	"github.com/oakmound/oak/collision"
 	"github.com/oakmound/oak/dlog"
@@ -50,22 +51,38 @@ func sceneLoop(first string) {
 
 	SceneMap.CurrentScene = "loading"
 
+	// resuming is set when the current scene was suspended and
+	// is being resumed, rather than started.
+	resuming := false
+
 	for {
-		ViewPos = image.Point{0, 0}
-		updateScreen(0, 0)
-		useViewBounds = false
+		if !resuming {
+			ViewPos = image.Point{0, 0}
+			updateScreen(0, 0)
+			useViewBounds = false
+		}
 
+		atomic.AddInt64(&scenesStarted, 1)
 		dlog.Info("Scene Start", SceneMap.CurrentScene)
 		scen, ok := SceneMap.GetCurrent()
 		if !ok {
 			dlog.Error("Unknown scene", SceneMap.CurrentScene)
 			panic("Unknown scene " + SceneMap.CurrentScene)
 		}
-		go func() {
-			dlog.Info("Starting scene in goroutine", SceneMap.CurrentScene)
-			scen.Start(prevScene, result.NextSceneInput)
+		go func(resuming bool) {
+			loadManifest(SceneMap.CurrentScene, scen.Manifest)
+			if resuming {
+				dlog.Info("Resuming scene in goroutine", SceneMap.CurrentScene)
+				if scen.Resume != nil {
+					scen.Resume(prevScene, result.NextSceneInput)
+				}
+			} else {
+				dlog.Info("Starting scene in goroutine", SceneMap.CurrentScene)
+				scen.Start(prevScene, result.NextSceneInput)
+			}
+			preloadManifests(scen.Manifest)
 			transitionCh <- true
-		}()
+		}(resuming)
 
 		sceneTransition(result)
 
@@ -81,61 +98,103 @@ func sceneLoop(first string) {
 		dlog.Info("Looping Scene")
 		cont := true
 
-		dlog.ErrorCheck(logicHandler.UpdateLoop(FrameRate, sceneCh))
-
-		for cont {
-			select {
-			case <-sceneCh:
-				cont = scen.Loop()
-			case <-skipSceneCh:
-				cont = false
+		// The loading scene waits on assets, and would never end if
+		// it waited on frames to be stepped through as well.
+		stepping := conf.FrameStepping && SceneMap.CurrentScene != "loading"
+
+		if stepping {
+			for cont {
+				select {
+				case <-stepCh:
+					cont = stepFrame(scen)
+					stepDoneCh <- true
+				case <-skipSceneCh:
+					cont = false
+				}
+			}
+		} else {
+			dlog.ErrorCheck(logicHandler.UpdateLoop(FrameRate, sceneCh))
+
+			for cont {
+				select {
+				case <-sceneCh:
+					replayFrame()
+					cont = scen.Loop()
+				case <-skipSceneCh:
+					cont = false
+				}
 			}
 		}
 		dlog.Info("Scene End", SceneMap.CurrentScene)
 
 		// We don't want enterFrames going off between scenes
-		dlog.ErrorCheck(logicHandler.Stop())
+		if !stepping {
+			dlog.ErrorCheck(logicHandler.Stop())
+		}
 		prevScene = SceneMap.CurrentScene
 
 		// Send a signal to stop drawing
 		drawCh <- true
 
-		// Reset any ongoing delays
-	delayLabel:
-		for {
-			select {
-			case timing.ClearDelayCh <- true:
-			default:
-				break delayLabel
-			}
+		// How the scene is left is decided before the engine is reset,
+		// so that End is called after the reset, and anything it creates
+		// carries into the next scene
+		var exit scene.Exit
+		if scen.Leave != nil {
+			exit = scen.Leave()
+		}
+		if exit.Push && exit.Pop {
+			dlog.Error("Scene", prevScene, "is leaving by both pushing and popping, ending it instead")
+			exit.Push = false
+			exit.Pop = false
 		}
 
-		dlog.Verb("Resetting Engine")
-		// Reset transient portions of the engine
-		// We start by clearing the event bus to
-		// remove most ongoing code
-		logicHandler.Reset()
-		// We follow by clearing collision areas
-		// because otherwise collision function calls
-		// on non-entities (i.e. particles) can still
-		// be triggered and attempt to access an entity
-		dlog.Verb("Event Bus Reset")
-		collision.Clear()
-		mouse.Clear()
-		event.ResetEntities()
-		render.ResetDrawStack()
-		render.PreDraw()
-		dlog.Verb("Engine Reset")
+		resuming = false
+		suspended := ""
+		if exit.Push {
+			dlog.Verb("Suspending Scene")
+			pushScene(prevScene)
+		} else {
+			// Reset any ongoing delays
+		delayLabel:
+			for {
+				select {
+				case timing.ClearDelayCh <- true:
+				default:
+					break delayLabel
+				}
+			}
 
-		// Todo: Add in customizable loading scene between regular scenes,
-		// In addition to the existing customizable loading renderable?
+			if exit.Pop {
+				// Nothing from a popped scene persists
+				resetEngine(scene.Persist{})
+				var ok bool
+				if suspended, ok = popScene(); ok {
+					dlog.Verb("Resuming Scene")
+					resuming = true
+				} else {
+					dlog.Warn("Popped a scene with no suspended scenes")
+				}
+			} else {
+				resetEngine(exit.Persist)
+			}
+		}
 
-		SceneMap.CurrentScene, result = scen.End()
+		var nextScene string
+		nextScene, result = scen.End()
 		// For convenience, we allow the user to return nil
 		// but it gets translated to an empty result
 		if result == nil {
 			result = new(scene.Result)
 		}
+		if resuming {
+			nextScene = suspended
+		}
+
+		// Todo: Add in customizable loading scene between regular scenes,
+		// In addition to the existing customizable loading renderable?
+
+		SceneMap.CurrentScene = nextScene
 
 		if !debugResetInProgress {
 			debugResetInProgress = true
@@ -146,3 +205,36 @@ func sceneLoop(first string) {
 		}
 	}
 }
+
+// resetEngine resets transient portions of the engine,
+// other than those which should persist into the next scene.
+func resetEngine(persist scene.Persist) {
+	dlog.Verb("Resetting Engine")
+	// We start by clearing the event bus to
+	// remove most ongoing code
+	if !persist.Bindings {
+		logicHandler.Reset()
+	} else if bus, ok := logicHandler.(*event.Bus); ok {
+		// Frames are still counted from the start of each scene
+		bus.ResetFrames()
+	}
+	// We follow by clearing collision areas
+	// because otherwise collision function calls
+	// on non-entities (i.e. particles) can still
+	// be triggered and attempt to access an entity
+	dlog.Verb("Event Bus Reset")
+	if !persist.Collision {
+		collision.Clear()
+	}
+	if !persist.MouseCollision {
+		mouse.Clear()
+	}
+	if !persist.Entities {
+		resetSceneEntities()
+	}
+	if !persist.DrawStack {
+		render.ResetDrawStack()
+		render.PreDraw()
+	}
+	dlog.Verb("Engine Reset")
+}
@@ -0,0 +1,114 @@
+package oak
+
+import (
+	"image"
+
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/dlog"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/mouse"
+	"github.com/oakmound/oak/render"
+)
+
+// A suspendedScene holds the engine state of a scene that
+// has had another scene pushed on top of it.
+type suspendedScene struct {
+	name          string
+	bus           event.SuspendedBus
+	highestID     event.CID
+	collisionTree *collision.Tree
+	mouseTree     *collision.Tree
+	drawStack     *render.DrawStack
+	viewPos       image.Point
+	useViewBounds bool
+	viewBounds    rect
+}
+
+var (
+	// sceneStack holds suspended scenes, most recently suspended last.
+	// It is only modified while the draw loop is not drawing scenes.
+	sceneStack []suspendedScene
+)
+
+// SuspendedScenes returns the names of all scenes which are suspended
+// beneath the current scene, from the bottom of the stack up.
+func SuspendedScenes() []string {
+	names := make([]string, len(sceneStack))
+	for i, s := range sceneStack {
+		names[i] = s.name
+	}
+	return names
+}
+
+// pushScene suspends the current engine state under the given scene name
+// and replaces it with empty state for the next scene.
+func pushScene(name string) {
+	s := suspendedScene{
+		name:          name,
+		highestID:     event.HighestID(),
+		collisionTree: collision.DefTree,
+		mouseTree:     mouse.DefTree,
+		drawStack:     render.GlobalDrawStack,
+		viewPos:       ViewPos,
+		useViewBounds: useViewBounds,
+		viewBounds:    viewBounds,
+	}
+	if bus, ok := logicHandler.(*event.Bus); ok {
+		s.bus = bus.Suspend()
+	} else {
+		dlog.Warn("Logic handler does not support suspension, bindings will not be suspended")
+	}
+	// The next scene should not reuse the slots of this scene's entities
+	event.ReserveEntities(s.highestID)
+	// These won't error, as they use the default tree settings
+	collision.DefTree, _ = collision.NewTree()
+	mouse.DefTree, _ = collision.NewTree()
+	render.ResetDrawStack()
+	sceneStack = append(sceneStack, s)
+}
+
+// popScene restores the engine state of the most recently suspended scene,
+// returning its name. If there are no suspended scenes, it returns false.
+// The current scene's state should already have been reset.
+func popScene() (string, bool) {
+	if len(sceneStack) == 0 {
+		return "", false
+	}
+	s := sceneStack[len(sceneStack)-1]
+	sceneStack = sceneStack[:len(sceneStack)-1]
+	if bus, ok := logicHandler.(*event.Bus); ok {
+		bus.Resume(s.bus)
+	}
+	event.ResetEntitiesAfter(s.highestID)
+	if len(sceneStack) > 0 {
+		event.ReserveEntities(sceneStack[len(sceneStack)-1].highestID)
+	} else {
+		event.ReserveEntities(0)
+	}
+	collision.DefTree = s.collisionTree
+	mouse.DefTree = s.mouseTree
+	render.GlobalDrawStack = s.drawStack
+	ViewPos = s.viewPos
+	useViewBounds = s.useViewBounds
+	viewBounds = s.viewBounds
+	updateScreen(ViewPos.X, ViewPos.Y)
+	return s.name, true
+}
+
+// resetSceneEntities drops the entities created by the current scene,
+// leaving those of any suspended scenes.
+func resetSceneEntities() {
+	if len(sceneStack) == 0 {
+		event.ResetEntities()
+		return
+	}
+	event.ResetEntitiesAfter(sceneStack[len(sceneStack)-1].highestID)
+}
+
+// drawSuspendedScenes draws the draw stacks of suspended scenes, from the
+// bottom of the stack up, at the viewports they were suspended with.
+func drawSuspendedScenes(buff *image.RGBA) {
+	for _, s := range sceneStack {
+		s.drawStack.Draw(buff, s.viewPos, ScreenWidth, ScreenHeight)
+	}
+}
@@ -0,0 +1,133 @@
+package oak
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/oakmound/oak/collision"
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/scene"
+	"github.com/stretchr/testify/assert"
+)
+
+type stackEnt struct{}
+
+func (se stackEnt) Init() event.CID {
+	return event.NextID(se)
+}
+
+func TestSceneStack(t *testing.T) {
+	resetOak()
+	testinit()
+
+	var baseFrames, resumes int32
+	baseLeaves := 0
+	var baseCID event.CID
+	baseSpace := collision.NewUnassignedSpace(-100, -100, 10, 10)
+	AddScene("stackBase", scene.Scene{
+		Start: func(string, interface{}) {
+			baseCID = stackEnt{}.Init()
+			baseCID.Bind(func(int, interface{}) int {
+				atomic.AddInt32(&baseFrames, 1)
+				return 0
+			}, event.Enter)
+			collision.Add(baseSpace)
+		},
+		Loop: func() bool { return true },
+		Leave: func() scene.Exit {
+			baseLeaves++
+			return scene.Exit{Push: baseLeaves == 1}
+		},
+		End: func() (string, *scene.Result) {
+			if baseLeaves == 1 {
+				return "stackTop", nil
+			}
+			return "blank", nil
+		},
+		Resume: func(prevScene string, data interface{}) {
+			assert.Equal(t, "stackTop", prevScene)
+			assert.Equal(t, "result", data)
+			atomic.AddInt32(&resumes, 1)
+		},
+	})
+	var topCID event.CID
+	AddScene("stackTop", scene.Scene{
+		Start: func(prevScene string, data interface{}) {
+			assert.Equal(t, "stackBase", prevScene)
+			topCID = stackEnt{}.Init()
+		},
+		Loop: func() bool { return true },
+		Leave: func() scene.Exit {
+			return scene.Exit{Pop: true}
+		},
+		End: func() (string, *scene.Result) {
+			return "ignored", &scene.Result{NextSceneInput: "result"}
+		},
+	})
+
+	testNextScene <- "stackBase"
+	skipSceneCh <- true
+	sleep()
+	assert.True(t, atomic.LoadInt32(&baseFrames) > 0)
+	assert.Equal(t, []string{}, SuspendedScenes())
+
+	// Push stackTop over stackBase
+	skipSceneCh <- true
+	sleep()
+	assert.Equal(t, []string{"stackBase"}, SuspendedScenes())
+	assert.Equal(t, "stackTop", SceneMap.CurrentScene)
+	frames := atomic.LoadInt32(&baseFrames)
+	sleep()
+	assert.Equal(t, frames, atomic.LoadInt32(&baseFrames))
+	assert.Empty(t, collision.Hits(baseSpace))
+	assert.True(t, topCID.Index() > baseCID.Index())
+	assert.NotNil(t, baseCID.E())
+
+	// Pop back to stackBase
+	skipSceneCh <- true
+	sleep()
+	assert.Equal(t, []string{}, SuspendedScenes())
+	assert.Equal(t, "stackBase", SceneMap.CurrentScene)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&resumes))
+	assert.True(t, atomic.LoadInt32(&baseFrames) > frames)
+	assert.Equal(t, []*collision.Space{baseSpace}, collision.Hits(collision.NewUnassignedSpace(-95, -95, 1, 1)))
+	assert.NotNil(t, baseCID.E())
+	assert.Nil(t, topCID.E())
+
+	skipSceneCh <- true
+	sleep()
+	assert.Equal(t, "blank", SceneMap.CurrentScene)
+}
+
+func TestScenePushAndPop(t *testing.T) {
+	resetOak()
+	testinit()
+
+	var cid event.CID
+	AddScene("pushAndPop", scene.Scene{
+		Start: func(string, interface{}) {
+			cid = stackEnt{}.Init()
+		},
+		Loop: func() bool { return true },
+		Leave: func() scene.Exit {
+			return scene.Exit{Push: true, Pop: true}
+		},
+		End: func() (string, *scene.Result) {
+			return "blank", nil
+		},
+	})
+
+	testNextScene <- "pushAndPop"
+	skipSceneCh <- true
+	sleep()
+	assert.Equal(t, "pushAndPop", SceneMap.CurrentScene)
+	assert.NotNil(t, cid.E())
+
+	// The scene is neither suspended nor replaced by a resumed scene,
+	// but ended
+	skipSceneCh <- true
+	sleep()
+	assert.Equal(t, "blank", SceneMap.CurrentScene)
+	assert.Equal(t, []string{}, SuspendedScenes())
+	assert.Nil(t, cid.E())
+}
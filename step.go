@@ -0,0 +1,42 @@
+package oak
+
+import (
+	"github.com/oakmound/oak/dlog"
+	"github.com/oakmound/oak/scene"
+)
+
+// Step advances the engine by n logical frames, drawing one frame after each
+// logical frame. It returns once every bindable triggered on EnterFrame for
+// those frames has returned. Step only has an effect if oak was initialized
+// with FrameStepping set in its Config.
+//
+// Step waits for the current scene to start looping before stepping, so frames
+// that would cross a scene boundary are taken in the next scene. The built in
+// loading scene is not stepped, as it waits on assets loading instead.
+//
+// Step may be called from several goroutines at once. Their frames are
+// interleaved, and each call returns once its own frames are done.
+func Step(n int) {
+	if !conf.FrameStepping {
+		dlog.Warn("Step called without frame stepping enabled")
+		return
+	}
+	for i := 0; i < n; i++ {
+		stepCh <- true
+		<-stepDoneCh
+	}
+}
+
+// stepFrame runs one logical frame and one draw frame of the given scene,
+// returning whether the scene should continue.
+func stepFrame(scen scene.Scene) bool {
+	replayFrame()
+	// Bindings are resolved before every frame so that they take effect
+	// at a consistent point, instead of whenever the bus next resolves them.
+	dlog.ErrorCheck(logicHandler.Flush())
+	dlog.ErrorCheck(logicHandler.Update())
+	cont := scen.Loop()
+	drawStepCh <- true
+	<-drawStepCh
+	return cont
+}
@@ -0,0 +1,70 @@
+package oak
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/oakmound/oak/event"
+	"github.com/oakmound/oak/headless"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStep(t *testing.T) {
+	resetOak()
+	testinit()
+
+	// Without frame stepping, Step does nothing
+	Step(1)
+
+	// The next scene will be stepped through
+	conf.FrameStepping = true
+	skipSceneCh <- true
+	sleep()
+
+	frames := []int{}
+	framesLock := sync.Mutex{}
+	event.GlobalBind(func(_ int, frame interface{}) int {
+		framesLock.Lock()
+		frames = append(frames, frame.(int))
+		framesLock.Unlock()
+		return 0
+	}, event.Enter)
+
+	publishes := headless.DefaultScreen.Window().Publishes()
+	Step(3)
+	assert.Equal(t, []int{0, 1, 2}, frames)
+	assert.Equal(t, 3, logicHandler.FramesElapsed())
+	assert.True(t, headless.DefaultScreen.Window().Publishes() >= publishes+3)
+
+	// Stepping does not happen on its own
+	sleep()
+	assert.Equal(t, []int{0, 1, 2}, frames)
+
+	Step(2)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, frames)
+
+	// Concurrent steps each wait on their own frames
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				framesLock.Lock()
+				before := len(frames)
+				framesLock.Unlock()
+				Step(1)
+				framesLock.Lock()
+				assert.True(t, len(frames) > before)
+				framesLock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 165, logicHandler.FramesElapsed())
+	assert.Len(t, frames, 165)
+
+	conf.FrameStepping = false
+	skipSceneCh <- true
+	sleep()
+}
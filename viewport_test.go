@@ -2,6 +2,7 @@ package oak
 
 import (
 	"image"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,14 +11,33 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var (
+	initOnce sync.Once
+	// testNextScene receives the scene the blank test scene will move
+	// to once it next ends. It is a channel rather than a string, as it
+	// is sent to by tests while the scene loop receives from it.
+	testNextScene = make(chan string, 1)
+)
+
 func testinit() {
+	initOnce.Do(testinitOnce)
+}
+
+func testinitOnce() {
 	SceneMap.Add("blank",
 		// Initialization function
 		func(prevScene string, inData interface{}) {},
 		// Loop to continue or stop current scene
 		func() bool { return true },
 		// Exit to transition to next scene
-		func() (nextScene string, result *scene.Result) { return "blank", nil })
+		func() (nextScene string, result *scene.Result) {
+			select {
+			case nextScene = <-testNextScene:
+				return nextScene, nil
+			default:
+				return "blank", nil
+			}
+		})
 	go Init("blank")
 	time.Sleep(2 * time.Second)
 	// Assert that nothing went wrong